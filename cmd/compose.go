@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aerospike/asconfig/conf"
+)
+
+const (
+	composeArgMin = 2
+
+	// composePatchKey is the overlay key of a section or named list item that
+	// changes how it is composed.
+	composePatchKey = "$patch"
+	// composePatchDelete removes the section or named list item.
+	composePatchDelete = "delete"
+	// composePatchReplace replaces the section or named list item instead of merging it.
+	composePatchReplace = "replace"
+)
+
+var (
+	errComposeTooFewArgs    = fmt.Errorf("compose requires at least %d arguments: <base> <overlay>...", composeArgMin)
+	errInvalidComposeLayer  = errors.New("overlay must be a yaml map of configuration sections")
+	errInvalidComposePatch  = errors.New("invalid $patch, valid options are: delete, replace")
+	errInvalidComposeDelete = errors.New("$patch: delete requires a named list item")
+)
+
+func newComposeCmd() *cobra.Command {
+	res := &cobra.Command{
+		Use:   "compose [flags] <path/to/base> <path/to/overlay>...",
+		Short: "Compose an Aerospike configuration from a base and yaml overlays.",
+		Long: `Compose deep merges yaml overlays onto a base configuration file, in order.
+				Overlays are partial configurations in the asconfig yaml format.
+				Sections are merged key by key, named list items such as namespaces,
+				sets, xdr dcs and logging sinks are merged by name, and other values
+				and lists are replaced by the overlay.
+				A setting or section set to null in an overlay is deleted.
+				A named list item with "$patch: delete" is deleted, and a section or
+				named list item with "$patch: replace" replaces the base instead of
+				being merged into it.
+				The composed configuration is validated for the --aerospike-version,
+				or the version in the base metadata, unless --force is used. It is
+				written in the Aerospike config format unless --to is used.`,
+		Example: `
+				# Compose the prod config for rack 2
+				asconfig compose -a 7.2.0 base.yaml overlays/prod.yaml overlays/rack-2.yaml -o aerospike.conf
+				# An overlay that raises proto-fd-max, changes the test namespace and deletes the bar namespace
+				service:
+				  proto-fd-max: 100000
+				namespaces:
+				  - name: test
+				    replication-factor: 3
+				  - name: bar
+				    $patch: delete`,
+		RunE: runComposeCommand,
+	}
+
+	res.Flags().AddFlagSet(getCommonFlags())
+	res.Flags().BoolP("force", "f", false, "Write the composed configuration even if it fails validation.")
+	res.Flags().StringP("output", "o", os.Stdout.Name(), "File path to write output to")
+	res.Flags().
+		StringP("format", "F", "conf", "The format of the base file. Valid options are: yaml, yml, json, toml, hcl, and conf.")
+	res.Flags().
+		String("to", "", "The format of the composed configuration. Valid options are: yaml, yml, json, toml, hcl, and conf. Defaults to conf.")
+
+	res.Version = VERSION
+
+	return res
+}
+
+func runComposeCommand(cmd *cobra.Command, args []string) error {
+	logger.Debug("Running compose command")
+
+	if len(args) < composeArgMin {
+		return errComposeTooFewArgs
+	}
+
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Processing flag force value=%t", force)
+
+	outFmt, err := getToFormat(cmd, asConf.AeroConfig)
+	if err != nil {
+		return err
+	}
+
+	basePath := args[0]
+
+	baseFormat, err := getConfFileFormat(basePath, cmd)
+	if err != nil {
+		return err
+	}
+
+	baseData, err := os.ReadFile(basePath)
+	if err != nil {
+		return err
+	}
+
+	version, err := getSourceVersion(cmd, baseData)
+	if err != nil && !force {
+		return err
+	}
+
+	baseConf, err := conf.NewASConfigFromBytes(mgmtLibLogger, baseData, baseFormat)
+	if err != nil {
+		return fmt.Errorf("%s: %w", basePath, err)
+	}
+
+	composed, err := migrationConfigMap(baseConf)
+	if err != nil {
+		return err
+	}
+
+	for _, overlayPath := range args[1:] {
+		logger.Debugf("Composing overlay %s", overlayPath)
+
+		overlay, err := loadComposeOverlay(overlayPath)
+		if err != nil {
+			return fmt.Errorf("%s: %w", overlayPath, err)
+		}
+
+		composed, err = composeMaps(composed, overlay)
+		if err != nil {
+			return fmt.Errorf("%s: %w", overlayPath, err)
+		}
+	}
+
+	composedConf, err := asConf.NewMapAsConfig(mgmtLibLogger, composed)
+	if err != nil {
+		return err
+	}
+
+	out, err := conf.NewConfigMarshaller(composedConf, outFmt).MarshalText()
+	if err != nil {
+		return err
+	}
+
+	mdata := map[string]string{metaKeyAsconfigVersion: VERSION}
+	if version != "" {
+		mdata[metaKeyAerospikeVersion] = version
+	}
+
+	out, err = addMetaData(out, outFmt, nil, nil, mdata)
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		verrs, errValidate := newSourceValidator(
+			composedConf, out, outFmt, version, conf.RuleOptions{},
+		).Validate()
+		if verrs != nil && len(verrs.Errors) > 0 {
+			fmt.Fprintf(cmd.OutOrStderr(), "Validation of the composed configuration against %s:\n%s", version, verrs.Error())
+		}
+
+		if errValidate != nil {
+			return errors.Join(errValidate, ErrSilent)
+		}
+	}
+
+	return writeConvertedOutput(cmd, basePath, outFmt, out)
+}
+
+// loadComposeOverlay reads a yaml overlay as generic json types.
+func loadComposeOverlay(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var overlay any
+	if err := yaml.Unmarshal(data, &overlay); err != nil {
+		return nil, err
+	}
+
+	if overlay == nil {
+		return map[string]any{}, nil
+	}
+
+	// round trip through json so overlay values have the types of the base config
+	jsonData, err := json.Marshal(overlay)
+	if err != nil {
+		return nil, errInvalidComposeLayer
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.UseNumber()
+
+	var res map[string]any
+	if err := dec.Decode(&res); err != nil {
+		return nil, errInvalidComposeLayer
+	}
+
+	return normalizeJSONNumbers(res).(map[string]any), nil //nolint:errcheck // maps stay maps
+}
+
+// composeMaps merges the overlay section onto the base section.
+func composeMaps(base, overlay map[string]any) (map[string]any, error) {
+	res := make(map[string]any, len(base))
+	for key, val := range base {
+		res[key] = val
+	}
+
+	for key, overlayVal := range overlay {
+		if key == composePatchKey {
+			continue
+		}
+
+		if overlayVal == nil {
+			delete(res, key)
+			continue
+		}
+
+		composed, keep, err := composeValues(res[key], overlayVal)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+
+		if keep {
+			res[key] = composed
+		} else {
+			delete(res, key)
+		}
+	}
+
+	return res, nil
+}
+
+// composeValues merges an overlay value onto a base value. It returns false if
+// the overlay deletes the value.
+func composeValues(base, overlay any) (any, bool, error) {
+	switch overlayVal := overlay.(type) {
+	case map[string]any:
+		patch, err := composePatch(overlayVal)
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch patch {
+		case composePatchDelete:
+			return nil, false, errInvalidComposeDelete
+		case composePatchReplace:
+			return stripComposePatches(overlayVal), true, nil
+		}
+
+		baseMap, ok := base.(map[string]any)
+		if !ok {
+			return stripComposePatches(overlayVal), true, nil
+		}
+
+		res, err := composeMaps(baseMap, overlayVal)
+
+		return res, true, err
+	case []any:
+		baseList, ok := base.([]any)
+		if !ok || !isNamedList(baseList) || !isNamedList(overlayVal) {
+			return stripComposePatches(overlayVal), true, nil
+		}
+
+		res, err := composeNamedLists(baseList, overlayVal)
+
+		return res, true, err
+	default:
+		return overlay, true, nil
+	}
+}
+
+// composeNamedLists merges the overlay items onto the base items with the same
+// name. New items are appended.
+func composeNamedLists(base, overlay []any) ([]any, error) {
+	res := make([]any, len(base))
+	copy(res, base)
+
+	for _, item := range overlay {
+		itemMap, _ := item.(map[string]any)
+		name, _ := itemMap[keyNameField].(string)
+
+		patch, err := composePatch(itemMap)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		idx := -1
+
+		for i, baseItem := range res {
+			if baseName, _ := baseItem.(map[string]any)[keyNameField].(string); baseName == name {
+				idx = i
+				break
+			}
+		}
+
+		switch {
+		case patch == composePatchDelete:
+			if idx >= 0 {
+				res = append(res[:idx], res[idx+1:]...)
+			}
+		case idx < 0 || patch == composePatchReplace:
+			if idx < 0 {
+				res = append(res, stripComposePatches(itemMap))
+			} else {
+				res[idx] = stripComposePatches(itemMap)
+			}
+		default:
+			baseItem, _ := res[idx].(map[string]any)
+
+			composed, err := composeMaps(baseItem, itemMap)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+
+			res[idx] = composed
+		}
+	}
+
+	return res, nil
+}
+
+// composePatch returns the $patch of an overlay section, empty if it has none.
+func composePatch(section map[string]any) (string, error) {
+	patch, ok := section[composePatchKey]
+	if !ok {
+		return "", nil
+	}
+
+	switch patch {
+	case composePatchDelete:
+		return composePatchDelete, nil
+	case composePatchReplace:
+		return composePatchReplace, nil
+	default:
+		return "", fmt.Errorf("%w: %v", errInvalidComposePatch, patch)
+	}
+}
+
+// stripComposePatches removes the $patch keys and null values of an overlay
+// value that is not merged with a base value.
+func stripComposePatches(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(val))
+
+		for key, child := range val {
+			if key == composePatchKey || child == nil {
+				continue
+			}
+
+			res[key] = stripComposePatches(child)
+		}
+
+		return res
+	case []any:
+		res := make([]any, 0, len(val))
+
+		for _, child := range val {
+			if childMap, ok := child.(map[string]any); ok && childMap[composePatchKey] == composePatchDelete {
+				continue
+			}
+
+			res = append(res, stripComposePatches(child))
+		}
+
+		return res
+	default:
+		return v
+	}
+}
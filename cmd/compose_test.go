@@ -0,0 +1,243 @@
+//go:build unit
+
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComposeMaps(t *testing.T) {
+	base := map[string]any{
+		"service": map[string]any{"proto-fd-max": int64(15000), "cluster-name": "cl1"},
+		"logging": []any{map[string]any{"name": "console", "any": "info"}},
+		"namespaces": []any{
+			map[string]any{
+				"name":               "test",
+				"replication-factor": int64(2),
+				"storage-engine":     map[string]any{"type": "memory"},
+			},
+			map[string]any{"name": "bar", "replication-factor": int64(2)},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		overlay map[string]any
+		want    map[string]any
+		wantErr error
+	}{
+		{
+			name: "merge sections and named lists",
+			overlay: map[string]any{
+				"service": map[string]any{"proto-fd-max": int64(20000)},
+				"namespaces": []any{
+					map[string]any{"name": "test", "default-ttl": int64(60)},
+					map[string]any{"name": "new", "replication-factor": int64(1)},
+				},
+			},
+			want: map[string]any{
+				"service": map[string]any{"proto-fd-max": int64(20000), "cluster-name": "cl1"},
+				"logging": []any{map[string]any{"name": "console", "any": "info"}},
+				"namespaces": []any{
+					map[string]any{
+						"name":               "test",
+						"replication-factor": int64(2),
+						"default-ttl":        int64(60),
+						"storage-engine":     map[string]any{"type": "memory"},
+					},
+					map[string]any{"name": "bar", "replication-factor": int64(2)},
+					map[string]any{"name": "new", "replication-factor": int64(1)},
+				},
+			},
+		},
+		{
+			name: "delete markers",
+			overlay: map[string]any{
+				"service": map[string]any{"cluster-name": nil},
+				"logging": nil,
+				"namespaces": []any{
+					map[string]any{"name": "bar", "$patch": "delete"},
+					map[string]any{"name": "missing", "$patch": "delete"},
+				},
+			},
+			want: map[string]any{
+				"service": map[string]any{"proto-fd-max": int64(15000)},
+				"namespaces": []any{
+					map[string]any{
+						"name":               "test",
+						"replication-factor": int64(2),
+						"storage-engine":     map[string]any{"type": "memory"},
+					},
+				},
+			},
+		},
+		{
+			name: "replace markers",
+			overlay: map[string]any{
+				"service": map[string]any{"$patch": "replace", "proto-fd-max": int64(20000)},
+				"namespaces": []any{
+					map[string]any{
+						"name":           "test",
+						"$patch":         "replace",
+						"storage-engine": map[string]any{"type": "device", "files": []any{"/opt/test.dat"}},
+					},
+				},
+			},
+			want: map[string]any{
+				"service": map[string]any{"proto-fd-max": int64(20000)},
+				"logging": []any{map[string]any{"name": "console", "any": "info"}},
+				"namespaces": []any{
+					map[string]any{
+						"name":           "test",
+						"storage-engine": map[string]any{"type": "device", "files": []any{"/opt/test.dat"}},
+					},
+					map[string]any{"name": "bar", "replication-factor": int64(2)},
+				},
+			},
+		},
+		{
+			name:    "invalid patch",
+			overlay: map[string]any{"service": map[string]any{"$patch": "merge"}},
+			wantErr: errInvalidComposePatch,
+		},
+		{
+			name:    "delete section",
+			overlay: map[string]any{"service": map[string]any{"$patch": "delete"}},
+			wantErr: errInvalidComposeDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := composeMaps(base, tt.overlay)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("composeMaps() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("composeMaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunComposeCommand(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	dir := t.TempDir()
+	paths := map[string]string{
+		"base.conf": "service {\n\tproto-fd-max 15000\n}\nlogging {\n\tconsole {\n\t\tcontext any info\n\t}\n}\n" +
+			"namespace test {\n\treplication-factor 2\n\tstorage-engine memory\n}\n" +
+			"namespace bar {\n\treplication-factor 2\n\tstorage-engine memory\n}\n",
+		"prod.yaml": "service:\n  proto-fd-max: 20000\nnamespaces:\n  - name: test\n    default-ttl: 60\n" +
+			"  - name: bar\n    $patch: delete\n",
+		"rack.yaml":    "namespaces:\n  - name: test\n    replication-factor: 3\n",
+		"invalid.yaml": "service:\n  proto-fd-max: many\n",
+		"patch.yaml":   "service:\n  $patch: merge\n",
+		"sc.yaml":      "namespaces:\n  - name: test\n    strong-consistency: true\n    default-ttl: 60\n",
+	}
+
+	for name, data := range paths {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+	}
+
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name        string
+		flags       []string
+		arguments   []string
+		wantErr     error
+		wantOutput  []string
+		wantMissing []string
+	}{
+		{
+			name:        "compose overlays",
+			flags:       []string{"--aerospike-version", "7.0.0"},
+			arguments:   []string{path("base.conf"), path("prod.yaml"), path("rack.yaml")},
+			wantOutput:  []string{"aerospike-server-version: 7.0.0", "proto-fd-max 20000", "default-ttl 60", "replication-factor 3"},
+			wantMissing: []string{"namespace bar"},
+		},
+		{
+			name:       "compose to yaml",
+			flags:      []string{"--aerospike-version", "7.0.0", "--to", "yaml"},
+			arguments:  []string{path("base.conf"), path("rack.yaml")},
+			wantOutput: []string{"proto-fd-max: 15000", "replication-factor: 3"},
+		},
+		{
+			name:       "compose only checks the schema",
+			flags:      []string{"--aerospike-version", "7.0.0"},
+			arguments:  []string{path("base.conf"), path("sc.yaml")},
+			wantOutput: []string{"strong-consistency true", "default-ttl 60"},
+		},
+		{
+			name:      "invalid composed config",
+			flags:     []string{"--aerospike-version", "7.0.0"},
+			arguments: []string{path("base.conf"), path("invalid.yaml")},
+			wantErr:   ErrSilent,
+		},
+		{
+			name:       "force invalid composed config",
+			flags:      []string{"--force"},
+			arguments:  []string{path("base.conf"), path("invalid.yaml")},
+			wantOutput: []string{"proto-fd-max many"},
+		},
+		{
+			name:      "invalid patch",
+			flags:     []string{"--aerospike-version", "7.0.0"},
+			arguments: []string{path("base.conf"), path("patch.yaml")},
+			wantErr:   errInvalidComposePatch,
+		},
+		{name: "missing version", arguments: []string{path("base.conf"), path("rack.yaml")}, wantErr: errMissingAerospikeVersion},
+		{name: "too few args", arguments: []string{path("base.conf")}, wantErr: errComposeTooFewArgs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outPath := filepath.Join(t.TempDir(), "composed")
+
+			cmd := newComposeCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			cmd.ParseFlags(append(tt.flags, "--output", outPath))
+			err := cmd.RunE(cmd, tt.arguments)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunE() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			out, err := os.ReadFile(outPath)
+			if err != nil {
+				t.Fatalf("Failed to read composed config: %v", err)
+			}
+
+			fields := strings.Join(strings.Fields(string(out)), " ")
+
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(fields, want) {
+					t.Errorf("RunE() output = %s, want it to contain %q", out, want)
+				}
+			}
+
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(fields, missing) {
+					t.Errorf("RunE() output = %s, want it not to contain %q", out, missing)
+				}
+			}
+		})
+	}
+}
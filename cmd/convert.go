@@ -24,9 +24,10 @@ func newConvertCmd() *cobra.Command {
 
 	res := &cobra.Command{
 		Use:   "convert [flags] <path/to/config_file>",
-		Short: "Convert between yaml and Aerospike config format.",
+		Short: "Convert between yaml, json, toml, hcl and Aerospike config format.",
 		Long: `Convert is used to convert between yaml and aerospike configuration
-				files. Input files are converted to their opposite format, yaml -> conf, conf -> yaml.
+				files. Input files are converted to their opposite format, yaml -> conf, conf -> yaml,
+				unless the --to option chooses the format of the converted file.
 				The convert command validates the configuration file for compatibility with the Aerospike
 				version passed to the --aerospike-version option, unless
 				version metadata is present in the file or the --force option is used.
@@ -45,7 +46,25 @@ func newConvertCmd() *cobra.Command {
 				If a file path is not provided, asconfig reads the file contents from stdin.
 				Ex: asconfig convert -a "6.4.0"
 				If the file has been converted by asconfig before, the --aerospike-version option is not needed.
-				Ex: asconfig convert -a "6.4.0" aerospike.yaml | asconfig convert --format conf`,
+				Ex: asconfig convert -a "6.4.0" aerospike.yaml | asconfig convert --format conf
+				Json configs have the structure of yaml configs and are converted to the
+				Aerospike config format. A config is converted to json when the output file
+				has a ".json" extension, its metadata is kept in the "_asconfig" field.
+				Ex: asconfig convert -a "7.0.0" aerospike.conf --output aerospike.json
+				Toml and hcl configs are also supported.
+				Ex: asconfig convert -a "7.0.0" --to toml aerospike.yaml
+				Converting a file to its own format writes it canonically formatted, with
+				sorted sections and normalized values, so convert can be used as a formatter.
+				Ex: asconfig convert -a "7.0.0" --to conf aerospike.conf --output aerospike.conf
+				The source can be a template with ${VAR} placeholders or Go template actions
+				that is rendered with the values passed to --values and --set before conversion.
+				Sources are only rendered when --values or --set is passed.
+				Variables without a value are errors.
+				Ex: asconfig convert -a "7.0.0" --values node-1.yaml --set node.rack-id=2 aerospike.tmpl.yaml
+				The --preserve-comments option keeps the comments and the ordering of the source
+				when converting between yaml and conf, so a file can be converted and back
+				without losing its annotations.
+				Ex: asconfig convert -a "7.0.0" --preserve-comments aerospike.conf --output aerospike.yaml`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return convertConfig(cmd, args, cfgData)
 		},
@@ -56,10 +75,16 @@ func newConvertCmd() *cobra.Command {
 	// aerospike-version is marked required in this cmd's PreRun if the --force flag is not provided
 	asCommonFlags := getCommonFlags()
 	res.Flags().AddFlagSet(asCommonFlags)
+	res.Flags().AddFlagSet(getTemplateFlags())
 	res.Flags().BoolP("force", "f", false, "Override checks for supported server version and config validation")
 	res.Flags().StringP("output", "o", os.Stdout.Name(), "File path to write output to")
 	res.Flags().
-		StringP("format", "F", "conf", "The format of the source file(s). Valid options are: yaml, yml, and conf.")
+		StringP("format", "F", "conf", "The format of the source file(s). Valid options are: yaml, yml, json, toml, hcl, and conf.")
+	res.Flags().
+		String("to", "", "The format of the converted file. Valid options are: yaml, yml, json, toml, hcl, and conf. "+
+			"Defaults to yaml for Aerospike config sources and to conf for other sources.")
+	res.Flags().Bool("preserve-comments", false, "Keep the comments and the ordering of the source. "+
+		"Supported when converting to yaml or conf.")
 
 	res.Version = VERSION
 
@@ -92,6 +117,13 @@ func convertConfig(cmd *cobra.Command, args []string, cfgData []byte) error {
 
 	logger.Debugf("Processing flag force value=%t", force)
 
+	preserve, err := cmd.Flags().GetBool("preserve-comments")
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Processing flag preserve-comments value=%t", preserve)
+
 	srcFormat, err := getConfFileFormat(srcPath, cmd)
 	if err != nil {
 		return err
@@ -99,7 +131,22 @@ func convertConfig(cmd *cobra.Command, args []string, cfgData []byte) error {
 
 	logger.Debugf("Processing flag format value=%v", srcFormat)
 
-	outFmt, err := determineOutputFormat(srcFormat)
+	defaultFmt, err := determineOutputFormat(srcFormat)
+	if err != nil {
+		return err
+	}
+
+	outputPath, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+
+	// json has no opposite format, it is written when the output file is json
+	if strings.EqualFold(filepath.Ext(outputPath), ".json") {
+		defaultFmt = conf.JSON
+	}
+
+	outFmt, err := getToFormat(cmd, defaultFmt)
 	if err != nil {
 		return err
 	}
@@ -113,7 +160,7 @@ func convertConfig(cmd *cobra.Command, args []string, cfgData []byte) error {
 	}
 
 	// load, validate, and convert
-	out, err := processConfigConversion(cfgData, srcFormat, outFmt, asVersion, force)
+	out, err := processConfigConversion(cfgData, srcFormat, outFmt, asVersion, force, preserve)
 	if err != nil {
 		return err
 	}
@@ -122,18 +169,18 @@ func convertConfig(cmd *cobra.Command, args []string, cfgData []byte) error {
 	return writeConvertedOutput(cmd, srcPath, outFmt, out)
 }
 
-// determineOutputFormat determines the output format based on source format.
+// determineOutputFormat determines the default output format based on source format.
+// Aerospike config files are converted to yaml, other formats to Aerospike config.
 func determineOutputFormat(srcFormat asConf.Format) (asConf.Format, error) {
-	switch srcFormat {
-	case asConf.AeroConfig:
-		return asConf.YAML, nil
-	case asConf.YAML:
-		return asConf.AeroConfig, nil
-	case asConf.Invalid:
-		return asConf.Invalid, fmt.Errorf("%w: %s", errInvalidFormat, srcFormat)
-	default:
+	if _, err := conf.FormatExtension(srcFormat); err != nil {
 		return asConf.Invalid, fmt.Errorf("%w: %s", errInvalidFormat, srcFormat)
 	}
+
+	if srcFormat == asConf.AeroConfig {
+		return asConf.YAML, nil
+	}
+
+	return asConf.AeroConfig, nil
 }
 
 // processConfigConversion handles loading, validation, and conversion.
@@ -141,17 +188,19 @@ func processConfigConversion(
 	cfgData []byte,
 	srcFormat, outFmt asConf.Format,
 	asVersion string,
-	force bool,
+	force, preserve bool,
 ) ([]byte, error) {
 	// load
-	asconfig, err := asConf.NewASConfigFromBytes(mgmtLibLogger, cfgData, srcFormat)
+	asconfig, err := conf.NewASConfigFromBytes(mgmtLibLogger, cfgData, srcFormat)
 	if err != nil {
 		return nil, err
 	}
 
 	// validate
 	if !force {
-		verrs, errValidate := conf.NewConfigValidator(asconfig, mgmtLibLogger, asVersion).Validate()
+		verrs, errValidate := newSourceValidator(
+			asconfig, cfgData, srcFormat, asVersion, conf.RuleOptions{},
+		).Validate()
 
 		// First handle validation process errors
 		if errValidate != nil {
@@ -159,31 +208,50 @@ func processConfigConversion(
 		}
 
 		// Then check if there are actual validation errors
-		if verrs != nil && len(verrs.Errors) > 0 {
+		if verrs != nil && verrs.HasErrors() {
 			return nil, verrs
 		}
+
+		// rule warnings don't prevent conversion
+		if verrs != nil && len(verrs.Errors) > 0 {
+			logger.Warnf("Config has warnings:\n%s", verrs.Error())
+		}
 	}
 
 	// convert
-	out, err := conf.NewConfigMarshaller(asconfig, outFmt).MarshalText()
-	if err != nil {
-		return nil, err
+	marshaller := conf.NewConfigMarshaller(asconfig, outFmt)
+
+	if preserve {
+		layout, errLayout := conf.NewSourceLayout(cfgData, srcFormat)
+		if errLayout != nil {
+			return nil, errLayout
+		}
+
+		marshaller = marshaller.WithSourceLayout(layout)
 	}
 
-	// prepend metadata to the config output
-	mtext, err := genMetaDataText(
-		cfgData,
-		nil,
-		map[string]string{
-			metaKeyAerospikeVersion: asVersion,
-			metaKeyAsconfigVersion:  VERSION,
-		},
-	)
+	out, err := marshaller.MarshalText()
 	if err != nil {
 		return nil, err
 	}
 
-	return append(mtext, out...), nil
+	mdata := map[string]string{
+		metaKeyAerospikeVersion: asVersion,
+		metaKeyAsconfigVersion:  VERSION,
+	}
+	metaSrc := cfgData
+
+	// preserved comments stay in place, only the metadata block is carried over
+	if preserve {
+		if err := metadata.UnmarshalBlock(cfgData, mdata); err != nil {
+			return nil, err
+		}
+
+		metaSrc = nil
+	}
+
+	// add metadata to the config output
+	return addMetaData(out, outFmt, metaSrc, nil, mdata)
 }
 
 // writeConvertedOutput handles writing the converted output to file or stdout.
@@ -228,16 +296,12 @@ func determineOutputPath(outputPath, srcPath string, outFmt asConf.Format) (stri
 		outFileName = strings.TrimSuffix(outFileName, filepath.Ext(outFileName))
 		outputPath = filepath.Join(outputPath, outFileName)
 
-		switch outFmt {
-		case asConf.YAML:
-			outputPath += ".yaml"
-		case asConf.AeroConfig:
-			outputPath += ".conf"
-		case asConf.Invalid:
-			return "", fmt.Errorf("output format unrecognized %w", errInvalidFormat)
-		default:
+		ext, err := conf.FormatExtension(outFmt)
+		if err != nil {
 			return "", fmt.Errorf("output format unrecognized %w", errInvalidFormat)
 		}
+
+		outputPath += "." + ext
 	}
 
 	return outputPath, nil
@@ -326,6 +390,16 @@ func validateAerospikeVersion(cmd *cobra.Command, args []string, force bool, cfg
 		return err
 	}
 
+	values, err := getTemplateValues(cmd)
+	if err != nil {
+		return err
+	}
+
+	data, err = renderConfigTemplate(data, values)
+	if err != nil {
+		return err
+	}
+
 	*cfgData = data
 
 	metaData := map[string]string{}
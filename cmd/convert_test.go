@@ -3,8 +3,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/aerospike/asconfig/conf/metadata"
 )
 
 type preTestConvert struct {
@@ -77,3 +84,246 @@ func TestPreRunConvert(t *testing.T) {
 		}
 	}
 }
+
+func TestRunEConvertJSON(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	dir := t.TempDir()
+	confPath := filepath.Join(dir, "aerospike.conf")
+	jsonPath := filepath.Join(dir, "aerospike.json")
+	roundTripPath := filepath.Join(dir, "round-trip.conf")
+
+	src := "service {\n\tproto-fd-max 15000\n}\nlogging {\n\tconsole {\n\t\tcontext any info\n\t}\n}\n" +
+		"namespace test {\n\treplication-factor 2\n\tstorage-engine memory\n}\n"
+	if err := os.WriteFile(confPath, []byte(src), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	convert := func(flags []string, srcPath string) error {
+		cmd := newConvertCmd()
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.ParseFlags(flags)
+
+		if err := cmd.PreRunE(cmd, []string{srcPath}); err != nil {
+			return err
+		}
+
+		return cmd.RunE(cmd, []string{srcPath})
+	}
+
+	if err := convert([]string{"-a", "7.0.0", "-o", jsonPath}, confPath); err != nil {
+		t.Fatalf("convert to json error = %v", err)
+	}
+
+	out, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("Failed to read json config: %v", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("convert output is not json: %v\n%s", err, out)
+	}
+
+	mdata, _ := doc[metadata.JSONField].(map[string]any)
+	if mdata[metaKeyAerospikeVersion] != "7.0.0" {
+		t.Errorf("convert json metadata = %v, want the aerospike-server-version 7.0.0", doc[metadata.JSONField])
+	}
+
+	// the server version is read from the json metadata
+	if err := convert([]string{"-o", roundTripPath}, jsonPath); err != nil {
+		t.Fatalf("convert from json error = %v", err)
+	}
+
+	roundTrip, err := os.ReadFile(roundTripPath)
+	if err != nil {
+		t.Fatalf("Failed to read converted config: %v", err)
+	}
+
+	if !strings.Contains(string(roundTrip), "# aerospike-server-version: 7.0.0") ||
+		!strings.Contains(strings.Join(strings.Fields(string(roundTrip)), " "), "proto-fd-max 15000") {
+		t.Errorf("convert from json = %s, want the source config and metadata", roundTrip)
+	}
+}
+
+func TestRunEConvertSkipsRules(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	dir := t.TempDir()
+	confPath := filepath.Join(dir, "aerospike.conf")
+	outPath := filepath.Join(dir, "aerospike.yaml")
+
+	// schema valid, but rejected by the strong-consistency-default-ttl rule of validate
+	src := "service {\n\tproto-fd-max 15000\n}\nlogging {\n\tconsole {\n\t\tcontext any info\n\t}\n}\n" +
+		"namespace test {\n\treplication-factor 2\n\tstrong-consistency true\n\tdefault-ttl 100\n" +
+		"\tstorage-engine memory\n}\n"
+	if err := os.WriteFile(confPath, []byte(src), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cmd := newConvertCmd()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.ParseFlags([]string{"-a", "7.0.0", "-o", outPath})
+
+	if err := cmd.PreRunE(cmd, []string{confPath}); err != nil {
+		t.Fatalf("PreRunE() error = %v", err)
+	}
+
+	if err := cmd.RunE(cmd, []string{confPath}); err != nil {
+		t.Fatalf("RunE() error = %v, want semantic rules to only run in validate", err)
+	}
+}
+
+func TestRunEConvertTo(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		flags      []string
+		wantErr    error
+		wantOutput []string
+	}{
+		{name: "default", wantOutput: []string{"service:", "proto-fd-max: 15000"}},
+		{name: "toml", flags: []string{"--to", "toml"}, wantOutput: []string{"[service]", "proto-fd-max = 15000"}},
+		{name: "hcl", flags: []string{"--to", "hcl"}, wantOutput: []string{"service = {", "proto-fd-max = 15000"}},
+		{name: "same format", flags: []string{"--to", "conf"}, wantOutput: []string{"service {", "proto-fd-max 15000"}},
+		{name: "invalid", flags: []string{"--to", "ini"}, wantErr: errInvalidToFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outPath := filepath.Join(t.TempDir(), "converted")
+
+			cmd := newConvertCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.ParseFlags(append(tt.flags, "--force", "-o", outPath))
+
+			srcPath := "../testdata/cases/server70/server70.conf"
+			if err := cmd.PreRunE(cmd, []string{srcPath}); err != nil {
+				t.Fatalf("PreRunE() error = %v", err)
+			}
+
+			err := cmd.RunE(cmd, []string{srcPath})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunE() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			out, err := os.ReadFile(outPath)
+			if err != nil {
+				t.Fatalf("Failed to read converted config: %v", err)
+			}
+
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(strings.Join(strings.Fields(string(out)), " "), want) {
+					t.Errorf("RunE() output = %s, want it to contain %q", out, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunEConvertToSameFormat(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "aerospike.yaml")
+	src := "namespaces:\n- storage-engine: {type: memory}\n  name: test\n  replication-factor: 2\n" +
+		"logging:\n- {name: console, any: info}\nservice: {proto-fd-max: 15000}\n"
+
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// format the file in place
+	cmd := newConvertCmd()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.ParseFlags([]string{"-a", "7.0.0", "--to", "yaml", "-o", path})
+
+	if err := cmd.PreRunE(cmd, []string{path}); err != nil {
+		t.Fatalf("PreRunE() error = %v", err)
+	}
+
+	if err := cmd.RunE(cmd, []string{path}); err != nil {
+		t.Fatalf("RunE() error = %v", err)
+	}
+
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read formatted config: %v", err)
+	}
+
+	want := "# *** Aerospike Metadata Generated by Asconfig ***\n" +
+		"# aerospike-server-version: 7.0.0\n" +
+		"# asconfig-version: " + VERSION + "\n" +
+		"# *** End Aerospike Metadata ***\n\n" +
+		"logging:\n    - any: info\n      name: console\n" +
+		"namespaces:\n    - name: test\n      replication-factor: 2\n      storage-engine:\n        type: memory\n" +
+		"service:\n    proto-fd-max: 15000\n"
+	if string(out) != want {
+		t.Errorf("RunE() formatted = %q, want %q", out, want)
+	}
+}
+
+func TestRunEConvertPreserveComments(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	dir := t.TempDir()
+	confPath := filepath.Join(dir, "aerospike.conf")
+	yamlPath := filepath.Join(dir, "aerospike.yaml")
+	roundTripPath := filepath.Join(dir, "round_trip.conf")
+
+	src := "# cluster config\n\n" +
+		"service {\n    # owner: ops\n    proto-fd-max    15000 # fds\n}\n\n" +
+		"logging {\n\n    console {\n        context any    info\n    }\n}\n\n" +
+		"namespace zeta {\n    # keep two copies\n    replication-factor    2\n    storage-engine    memory\n}\n\n" +
+		"namespace alpha {\n    replication-factor    1\n    storage-engine    memory\n}\n"
+
+	if err := os.WriteFile(confPath, []byte(src), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	for _, tc := range [][]string{{confPath, yamlPath}, {yamlPath, roundTripPath}} {
+		cmd := newConvertCmd()
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.ParseFlags([]string{"-a", "7.0.0", "--preserve-comments", "-o", tc[1]})
+
+		if err := cmd.PreRunE(cmd, tc[:1]); err != nil {
+			t.Fatalf("PreRunE() error = %v", err)
+		}
+
+		if err := cmd.RunE(cmd, tc[:1]); err != nil {
+			t.Fatalf("RunE() error = %v", err)
+		}
+	}
+
+	out, err := os.ReadFile(roundTripPath)
+	if err != nil {
+		t.Fatalf("Failed to read converted config: %v", err)
+	}
+
+	want := "# *** Aerospike Metadata Generated by Asconfig ***\n" +
+		"# aerospike-server-version: 7.0.0\n" +
+		"# asconfig-version: " + VERSION + "\n" +
+		"# *** End Aerospike Metadata ***\n\n" + src
+	if string(out) != want {
+		t.Errorf("RunE() round trip = %q, want %q", out, want)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 
+	aero "github.com/aerospike/aerospike-client-go/v8"
 	lib "github.com/aerospike/aerospike-management-lib"
 	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
 	"github.com/aerospike/aerospike-management-lib/info"
@@ -25,8 +26,9 @@ const (
 	diffArgMax         = 2
 	diffServerArgMin   = 1 // For server diff, we need only one local file
 	diffServerArgMax   = 1
+	diffNodesHostMin   = 2 // For nodes diff, we need at least 2 nodes to compare
 	diffVersionsArgMin = 2 // For versions diff, we need exactly 2 versions
-	diffVersionsArgMax = 2
+	diffVersionsArgMax = 3 // and optionally a config file to report the upgrade impact on
 )
 
 // GetDiffCmd returns the diff command.
@@ -44,6 +46,8 @@ func newDiffCmd() *cobra.Command {
 				asconfig diff files aerospike1.yaml aerospike2.yaml
 				# Diff a local .conf file against a running server
 				asconfig diff server -h 127.0.0.1:3000  aerospike.conf
+				# Diff the configs of running nodes against each other
+				asconfig diff nodes -h 127.0.0.1:3000 -h 127.0.0.2:3000
 				# Compare configuration changes between versions
 				asconfig diff versions 7.0.0 8.1.0
 				# Compare configuration changes between versions and focus on specific configuration areas
@@ -56,11 +60,16 @@ func newDiffCmd() *cobra.Command {
 
 	res.Version = VERSION
 	res.Flags().
-		StringP("format", "F", "conf", "The format of the source file(s). Valid options are: yaml, yml, and conf.")
+		StringP("format", "F", "conf", "The format of the source file(s). Valid options are: yaml, yml, json, toml, hcl, and conf.")
+	res.Flags().
+		String("output-format", outputFormatText, "The format of the differences. Valid options are: text, json, yaml, and unified.")
+	addDiffRulesFlags(res)
+	addDiffEffectiveFlags(res)
 
 	// Add subcommands
 	res.AddCommand(newDiffFilesCmd())
 	res.AddCommand(newDiffServerCmd())
+	res.AddCommand(newDiffNodesCmd())
 	res.AddCommand(newDiffVersionsCmd())
 
 	return res
@@ -75,12 +84,20 @@ func newDiffFilesCmd() *cobra.Command {
 			It is used on two files of the same format from any format
 			supported by the asconfig tool, e.g. yaml or Aerospike config.
 			Schema validation is not performed on either file. The file names must end with
-			extensions signifying their formats, e.g. .conf or .yaml, or --format must be used.`,
+			extensions signifying their formats, e.g. .conf or .yaml, or --format must be used.
+			Use --output-format to report the differences as json or yaml records, or as a unified diff.
+			Use --ignore to skip settings by path glob and --rules to normalize values before they are compared.
+			Use --effective to fill in the schema defaults of omitted settings, so that a setting left
+			at its default and one omitted compare equal.`,
 		Example: `
 			# Compare two local configuration files
   				asconfig diff files aerospike1.conf aerospike2.conf
 			# Compare two local yaml configuration files
-				asconfig diff files --format yaml aerospike1.yaml aerospike2.yaml`,
+				asconfig diff files --format yaml aerospike1.yaml aerospike2.yaml
+			# Report the differences as json records
+				asconfig diff files --output-format json aerospike1.conf aerospike2.conf
+			# Compare the effective configurations of a 7.0.0 server
+				asconfig diff files --effective --aerospike-version 7.0.0 aerospike1.conf aerospike2.conf`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger.Debug("Running diff files command")
 			return runFileDiff(cmd, args)
@@ -88,7 +105,11 @@ func newDiffFilesCmd() *cobra.Command {
 	}
 	cmd.Version = VERSION
 	cmd.Flags().
-		StringP("format", "F", "conf", "The format of the source file(s). Valid options are: yaml, yml, and conf.")
+		StringP("format", "F", "conf", "The format of the source file(s). Valid options are: yaml, yml, json, toml, hcl, and conf.")
+	cmd.Flags().
+		String("output-format", outputFormatText, "The format of the differences. Valid options are: text, json, yaml, and unified.")
+	addDiffRulesFlags(cmd)
+	addDiffEffectiveFlags(cmd)
 
 	return cmd
 }
@@ -100,9 +121,21 @@ func newDiffServerCmd() *cobra.Command {
 		Long: `BETA: Diff is used to compare a local configuration file against the configuration of a running Aerospike server. 
 				This is useful for spotting drift between expected and actual Aerospike server configurations.
 				In this mode, only one config file path is required as an argument.
-				Note: The configuration file can be in yaml or conf format.`,
+				Note: The configuration file can be in yaml or conf format.
+				Use --output-format to report the differences as json or yaml records, or as a unified diff.
+				Use --ignore to skip node specific settings such as service.node-id, and --rules to
+				normalize units, case insensitive values and default values before they are compared.
+				Use --effective to fill in the schema defaults of settings omitted by either side.
+				Use --all-nodes to compare every node of the cluster, this reports the nodes that
+				differ from the local file and the settings on which the nodes diverge from each other.`,
 		Example: `Diff a local .conf file against a running server
-  				asconfig diff server -h 127.0.0.1:3000 aerospike.conf`,
+  				asconfig diff server -h 127.0.0.1:3000 aerospike.conf
+				Report the drift as json records for alerting
+  				asconfig diff server -h 127.0.0.1:3000 --output-format json aerospike.conf
+				Detect drift across all nodes of a cluster
+  				asconfig diff server -h 127.0.0.1:3000 --all-nodes aerospike.conf
+				Ignore node specific settings
+  				asconfig diff server -h 127.0.0.1:3000 --ignore service.node-id,network.service.access-address aerospike.conf`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger.Debug("Running server diff command")
 			return runServerDiff(cmd, args)
@@ -111,7 +144,13 @@ func newDiffServerCmd() *cobra.Command {
 
 	// Add format flag but hide it from help output as it will be automatically detected
 	cmd.Flags().
-		StringP("format", "F", "conf", "The format of the source file(s). Valid options are: yaml, yml, and conf.")
+		StringP("format", "F", "conf", "The format of the source file(s). Valid options are: yaml, yml, json, toml, hcl, and conf.")
+	cmd.Flags().
+		String("output-format", outputFormatText, "The format of the differences. Valid options are: text, json, yaml, and unified.")
+	addDiffRulesFlags(cmd)
+	addDiffEffectiveFlags(cmd)
+	cmd.Flags().
+		Bool("all-nodes", false, "Diff every node of the cluster against the local file and against each other.")
 
 	if err := cmd.Flags().MarkHidden("format"); err != nil {
 		logger.Errorf("Unable to hide format flag: %v", err)
@@ -129,9 +168,39 @@ func newDiffServerCmd() *cobra.Command {
 	return cmd
 }
 
+func newDiffNodesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nodes [flags]",
+		Short: "BETA: Diff the configurations of two or more running Aerospike nodes.",
+		Long: `BETA: Diff is used to compare the configurations of running Aerospike nodes against each other
+				when there is no local configuration file to compare them to.
+				The configuration of every node given with --host is generated as in diff server,
+				and the settings whose values differ across the nodes are shown as a matrix.`,
+		Example: `Diff the configurations of two nodes
+  				asconfig diff nodes -h 127.0.0.1:3000 -h 127.0.0.2:3000
+				Report the differing settings as json
+  				asconfig diff nodes -h 127.0.0.1:3000,127.0.0.2:3000 --output-format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger.Debug("Running nodes diff command")
+			return runNodesDiff(cmd, args)
+		},
+	}
+
+	cmd.Flags().
+		String("output-format", outputFormatText, "The format of the differences. Valid options are: text, json, and yaml.")
+	addDiffRulesFlags(cmd)
+
+	asFlagSet := aerospikeFlags.NewFlagSet(flags.DefaultWrapHelpString)
+	cmd.Flags().AddFlagSet(asFlagSet)
+	config.BindPFlags(asFlagSet, "cluster")
+	cmd.Version = VERSION
+
+	return cmd
+}
+
 func newDiffVersionsCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "versions [flags] <version1> <version2>",
+		Use:   "versions [flags] <version1> <version2> [path/to/config]",
 		Short: "Show configuration file difference between versions of the Aerospike server.",
 		Long: `Compare configuration schemas between two Aerospike server versions to understand
 			what changes when upgrading or downgrading. This command shows which configuration 
@@ -139,7 +208,12 @@ func newDiffVersionsCmd() *cobra.Command {
 
 			By default, detailed information is shown including property types, defaults, and descriptions.
 			Use --compact to show only configuration names for a minimal view.
-			Use --filter-path to focus on specific configuration sections.`,
+			Use --filter-path to focus on specific configuration sections.
+			Use --output-format to produce json, yaml or markdown output for other tools.
+
+			If a config file is given, only the changes that affect settings used by the
+			file are reported. This upgrade impact report lists the used settings that are
+			removed, renamed, retyped or have a changed default in the newer version.`,
 		Example: `
 			# Compare configuration changes between versions (detailed by default)
 			asconfig diff versions 7.0.0 7.2.0
@@ -154,6 +228,12 @@ func newDiffVersionsCmd() *cobra.Command {
 			# Combine compact view with filtering
 			asconfig diff versions 7.0.0 8.0.0 --compact --filter-path "service"
 
+			# Produce a markdown summary for release notes
+			asconfig diff versions 7.0.0 8.0.0 --output-format markdown
+
+			# Report the settings of a config file affected by an upgrade
+			asconfig diff versions 6.4.0 7.0.0 aerospike.conf
+
 			# List all available Aerospike server versions
 			asconfig list versions --verbose
 			`,
@@ -167,6 +247,10 @@ func newDiffVersionsCmd() *cobra.Command {
 		BoolP("compact", "c", false, "Show minimal output with only configuration names (default shows detailed information)")
 	cmd.Flags().
 		StringP("filter-path", "f", "", "Filter results to only show properties under the specified path (e.g., 'service', 'namespaces')")
+	cmd.Flags().
+		StringP("format", "F", "conf", "The format of the config file. Valid options are: yaml, yml, json, toml, hcl, and conf.")
+	cmd.Flags().
+		String("output-format", outputFormatText, "The format of the output. Valid options are: text, json, yaml, and markdown.")
 	cmd.Version = VERSION
 
 	return cmd
@@ -182,6 +266,11 @@ func runFileDiff(cmd *cobra.Command, args []string) error {
 		return errDiffTooManyArgs
 	}
 
+	outFmt, rules, err := getConfigDiffOptions(cmd)
+	if err != nil {
+		return err
+	}
+
 	path1 := args[0]
 	path2 := args[1]
 
@@ -219,24 +308,30 @@ func runFileDiff(cmd *cobra.Command, args []string) error {
 	// won't be marshaling these configs to text so use Invalid output format
 	// TODO decouple output format from asconf, probably pass it as an
 	// arg to marshal text
-	conf1, err := asConf.NewASConfigFromBytes(mgmtLibLogger, f1, fmt1)
+	conf1, err := conf.NewASConfigFromBytes(mgmtLibLogger, f1, fmt1)
+	if err != nil {
+		return err
+	}
+
+	conf2, err := conf.NewASConfigFromBytes(mgmtLibLogger, f2, fmt2)
 	if err != nil {
 		return err
 	}
 
-	conf2, err := asConf.NewASConfigFromBytes(mgmtLibLogger, f2, fmt2)
+	effectiveSchema, err := getEffectiveSchema(cmd, f1, f2)
 	if err != nil {
 		return err
 	}
 
 	// get flattened config maps
-	map1 := conf1.GetFlatMap()
-	map2 := conf2.GetFlatMap()
+	differences := diffConfigFlatMaps(*conf1.GetFlatMap(), *conf2.GetFlatMap(), rules, effectiveSchema)
 
-	diffs := diffFlatMaps(
-		*map1,
-		*map2,
-	)
+	if outFmt != outputFormatText {
+		report := configDiffReport{Left: path1, Right: path2, Differences: differences}
+		return renderConfigDiffResult(cmd, outFmt, report, conf1, conf2, fmt1)
+	}
+
+	diffs := formatFlatMapDiffs(differences)
 
 	if len(diffs) > 0 {
 		fmt.Fprintf(
@@ -253,6 +348,77 @@ func runFileDiff(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// getConfigDiffOutputFormat returns the output format of the files and server diffs.
+func getConfigDiffOutputFormat(cmd *cobra.Command) (string, error) {
+	return getOutputFormat(cmd, outputFormatText, outputFormatJSON, outputFormatYAML, outputFormatUnified)
+}
+
+// getConfigDiffOptions returns the output format and normalization rules of the
+// files and server diffs. The unified format diffs the configs as written so it
+// can't be normalized or made effective.
+func getConfigDiffOptions(cmd *cobra.Command) (string, diffRules, error) {
+	outFmt, err := getConfigDiffOutputFormat(cmd)
+	if err != nil {
+		return "", diffRules{}, err
+	}
+
+	rules, err := getDiffRules(cmd)
+	if err != nil {
+		return "", diffRules{}, err
+	}
+
+	effective, err := cmd.Flags().GetBool("effective")
+	if err != nil {
+		return "", diffRules{}, err
+	}
+
+	if outFmt == outputFormatUnified && (effective || !rules.empty()) {
+		return "", diffRules{}, errDiffRulesUnifiedFormat
+	}
+
+	return outFmt, rules, nil
+}
+
+// addDiffRulesFlags adds the flags that normalize configs before they are diffed.
+func addDiffRulesFlags(cmd *cobra.Command) {
+	cmd.Flags().
+		StringSlice("ignore", nil, "Globs of config paths to ignore, e.g. service.node-id or 'namespaces.*.rack-id'. "+
+			"'*' matches any sequence of characters.")
+	cmd.Flags().
+		String("rules", "", "Path to a yaml file of normalization rules: ignored paths, size and time units, "+
+			"case insensitive values and default values. With rules, numbers of any type compare by value.")
+}
+
+// addDiffEffectiveFlags adds the flags that fill in schema defaults before configs are diffed.
+func addDiffEffectiveFlags(cmd *cobra.Command) {
+	cmd.Flags().
+		Bool("effective", false, "Fill in the schema defaults of omitted settings so that only differences in "+
+			"effective behaviour are shown. Implicit default values are marked.")
+	cmd.Flags().
+		StringP("aerospike-version", "a", "", "Aerospike server version whose schema defaults are used by --effective. "+
+			"Ex: 7.0.0.\nRequired with --effective unless a config file has the version in its metadata.")
+}
+
+// renderConfigDiffResult writes the differences in report to the command output in
+// outFmt and returns errDiffConfigsDiffer if there are any.
+func renderConfigDiffResult(
+	cmd *cobra.Command,
+	outFmt string,
+	report configDiffReport,
+	left, right *asConf.AsConfig,
+	format asConf.Format,
+) error {
+	if err := renderConfigDiff(cmd.OutOrStdout(), outFmt, report, left, right, format); err != nil {
+		return err
+	}
+
+	if len(report.Differences) > 0 {
+		return fmt.Errorf("%w: %w", errDiffConfigsDiffer, ErrSilent)
+	}
+
+	return nil
+}
+
 // runServerDiff handles comparing a local file against a running server.
 func runServerDiff(cmd *cobra.Command, args []string) error {
 	if len(args) < diffServerArgMin {
@@ -267,6 +433,11 @@ func runServerDiff(cmd *cobra.Command, args []string) error {
 		"This feature is currently in beta. Use at your own risk and please report any issue to support.",
 	)
 
+	outFmt, rules, err := getConfigDiffOptions(cmd)
+	if err != nil {
+		return err
+	}
+
 	localPath := args[0]
 	logger.Debugf("Comparing local file %s against server", localPath)
 
@@ -284,7 +455,7 @@ func runServerDiff(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create local config
-	localConf, err := asConf.NewASConfigFromBytes(mgmtLibLogger, localFile, localFormat)
+	localConf, err := conf.NewASConfigFromBytes(mgmtLibLogger, localFile, localFormat)
 	if err != nil {
 		return err
 	}
@@ -298,44 +469,49 @@ func runServerDiff(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("%w: %w", errUnableToCreateClientPolicy, err)
 	}
 
-	logger.Debugf("Retrieving Aerospike configuration from server")
-
 	asHosts := asCommonConfig.NewHosts()
-	asinfo := info.NewAsInfo(mgmtLibLogger, asHosts[0], asPolicy)
 
-	generatedConf, err := asConf.GenerateConf(mgmtLibLogger, asinfo, true)
+	allNodes, err := cmd.Flags().GetBool("all-nodes")
 	if err != nil {
-		return fmt.Errorf("%w: %w", errUnableToGenerateConfigFromServer, err)
+		return err
 	}
 
-	// Convert server config to the same format as local file to ensure same parsing path
-	serverConfHandler, err := asConf.NewMapAsConfig(mgmtLibLogger, generatedConf.Conf)
+	logger.Debugf("Processing flag all-nodes value=%v", allNodes)
+
+	effectiveSchema, err := getEffectiveSchema(cmd, localFile)
 	if err != nil {
-		return fmt.Errorf("%w: %w", errUnableToParseGeneratedServerConf, err)
+		return err
 	}
 
-	// Marshal server config to bytes in the same format as local file
-	serverConfigMarshaller := conf.NewConfigMarshaller(serverConfHandler, localFormat)
+	if allNodes && effectiveSchema != nil {
+		return errDiffEffectiveAllNodes
+	}
 
-	serverConfigBytes, err := serverConfigMarshaller.MarshalText()
-	if err != nil {
-		return fmt.Errorf("%w: %w", errUnableToMarshalServerConfig, err)
+	if allNodes {
+		return runClusterDiff(cmd, outFmt, rules, localPath, localConf, localFormat, asHosts[0], asPolicy)
 	}
 
-	// Parse server config bytes using the same path as local file
-	serverConf, err := asConf.NewASConfigFromBytes(mgmtLibLogger, serverConfigBytes, localFormat)
+	logger.Debugf("Retrieving Aerospike configuration from server")
+
+	serverConf, err := generateNodeConfig(asHosts[0], asPolicy, localFormat)
 	if err != nil {
-		return fmt.Errorf("%w: %w", errUnableToParseServerConfigBytes, err)
+		return err
 	}
 
 	// Get flattened config maps - now both should have the same data types
-	localMap := localConf.GetFlatMap()
-	serverMap := serverConf.GetFlatMap()
+	differences := diffConfigFlatMaps(*localConf.GetFlatMap(), *serverConf.GetFlatMap(), rules, effectiveSchema)
 
-	diffs := diffFlatMaps(
-		*localMap,
-		*serverMap,
-	)
+	if outFmt != outputFormatText {
+		report := configDiffReport{
+			Left:        localPath,
+			Right:       asHosts[0].String(),
+			Differences: differences,
+		}
+
+		return renderConfigDiffResult(cmd, outFmt, report, localConf, serverConf, localFormat)
+	}
+
+	diffs := formatFlatMapDiffs(differences)
 
 	if len(diffs) > 0 {
 		fmt.Fprintf(
@@ -351,6 +527,41 @@ func runServerDiff(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// generateNodeConfig generates the config of the node at host and parses it in
+// format, the format of the config it is compared to, so that both configs take
+// the same parsing path.
+func generateNodeConfig(host *aero.Host, policy *aero.ClientPolicy, format asConf.Format) (*asConf.AsConfig, error) {
+	asinfo := info.NewAsInfo(mgmtLibLogger, host, policy)
+	defer asinfo.Close()
+
+	generatedConf, err := asConf.GenerateConf(mgmtLibLogger, asinfo, true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errUnableToGenerateConfigFromServer, err)
+	}
+
+	// Convert server config to the same format as local file to ensure same parsing path
+	serverConfHandler, err := asConf.NewMapAsConfig(mgmtLibLogger, generatedConf.Conf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errUnableToParseGeneratedServerConf, err)
+	}
+
+	// Marshal server config to bytes in the same format as local file
+	serverConfigMarshaller := conf.NewConfigMarshaller(serverConfHandler, format)
+
+	serverConfigBytes, err := serverConfigMarshaller.MarshalText()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errUnableToMarshalServerConfig, err)
+	}
+
+	// Parse server config bytes using the same path as local file
+	serverConf, err := conf.NewASConfigFromBytes(mgmtLibLogger, serverConfigBytes, format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errUnableToParseServerConfigBytes, err)
+	}
+
+	return serverConf, nil
+}
+
 // runVersionsDiff compares the configuration between two Aerospike server versions.
 func runVersionsDiff(cmd *cobra.Command, args []string) error {
 	if len(args) < diffVersionsArgMin {
@@ -413,6 +624,11 @@ func runVersionsDiff(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to parse schema for version %s: %w", version2, unmarshalErr)
 	}
 
+	outFmt, err := getOutputFormat(cmd, outputFormatText, outputFormatJSON, outputFormatYAML, outputFormatMarkdown)
+	if err != nil {
+		return err
+	}
+
 	// Get flags - verbose is now the default, compact is the exception
 	compact, _ := cmd.Flags().GetBool("compact")
 	verbose := !compact // Verbose is the default behavior, compact overrides it
@@ -439,6 +655,14 @@ func runVersionsDiff(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if len(args) > diffVersionsArgMin {
+		return runUpgradeImpact(cmd, args[2], outFmt, summary, schemaLower, schemaUpper, filterSections)
+	}
+
+	if outFmt != outputFormatText {
+		return renderChangeSummaryRecord(cmd.OutOrStdout(), outFmt, newChangeSummaryRecord(summary, filterSections))
+	}
+
 	// Output the results
 	renderChangeSummary(summary, DiffOptions{
 		Verbose:        verbose,
@@ -448,6 +672,43 @@ func runVersionsDiff(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runUpgradeImpact reports the changes in summary that affect the settings used
+// by the config file at srcPath.
+func runUpgradeImpact(
+	cmd *cobra.Command,
+	srcPath, outFmt string,
+	summary ChangeSummary,
+	schemaLower, schemaUpper map[string]any,
+	filterSections map[string]struct{},
+) error {
+	srcFormat, err := getConfFileFormat(srcPath, cmd)
+	if err != nil {
+		return err
+	}
+
+	fdata, err := os.ReadFile(srcPath)
+	if err != nil {
+		return err
+	}
+
+	asconfig, err := conf.NewASConfigFromBytes(mgmtLibLogger, fdata, srcFormat)
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", srcPath, err)
+	}
+
+	impacts, err := upgradeImpacts(asconfig, summary, schemaLower, schemaUpper)
+	if err != nil {
+		return fmt.Errorf("failed to compute upgrade impact: %w", err)
+	}
+
+	return renderUpgradeImpact(cmd.OutOrStdout(), outFmt, upgradeImpactReport{
+		Source:       srcPath,
+		LowerVersion: summary.LowerVersion,
+		UpperVersion: summary.UpperVersion,
+		Impacts:      filterImpacts(impacts, filterSections),
+	})
+}
+
 // parseFilterPath parses the filter-path flag and returns a map of sections to filter.
 func parseFilterPath(filterPath string) (map[string]struct{}, error) {
 	filterSections := make(map[string]struct{})
@@ -466,11 +727,62 @@ func parseFilterPath(filterPath string) (map[string]struct{}, error) {
 	return filterSections, nil
 }
 
+// diffConfigFlatMaps reports the differences between the flattened config maps
+// m1 and m2 after normalizing them with rules. If effectiveSchema is not nil the
+// settings omitted by either side are first filled in with its defaults.
+func diffConfigFlatMaps(m1, m2 map[string]any, rules diffRules, effectiveSchema map[string]any) []configDiffRecord {
+	if effectiveSchema == nil {
+		return diffFlatMapRecords(rules.normalize(m1), rules.normalize(m2))
+	}
+
+	effective := newEffectiveFlatMaps(effectiveSchema, m1, m2)
+
+	res := diffFlatMapRecords(rules.normalize(effective.left), rules.normalize(effective.right))
+	effective.markImplicit(res)
+
+	return res
+}
+
 // diffFlatMaps reports differences between flattened config maps
 // this only works for maps 1 layer deep as produced by the management
 // lib's flattenConf function.
 func diffFlatMaps(m1, m2 map[string]any) []string {
-	var res []string
+	return formatFlatMapDiffs(diffFlatMapRecords(m1, m2))
+}
+
+// formatFlatMapDiffs formats differences in the diffFlatMaps text format, '<' are
+// the left values and '>' the right values. Implicit default values are marked
+// with implicitSuffix.
+func formatFlatMapDiffs(records []configDiffRecord) []string {
+	res := make([]string, 0, len(records))
+
+	for _, record := range records {
+		switch record.Kind {
+		case diffKindAdded:
+			res = append(res, fmt.Sprintf(">: %s\n", record.key))
+		case diffKindRemoved:
+			res = append(res, fmt.Sprintf("<: %s\n", record.key))
+		default:
+			left, right := fmt.Sprint(record.Left), fmt.Sprint(record.Right)
+
+			switch record.Implicit {
+			case implicitLeft:
+				left += implicitSuffix
+			case implicitRight:
+				right += implicitSuffix
+			}
+
+			res = append(res, fmt.Sprintf("%s:\n\t<: %s\n\t>: %s\n", record.key, left, right))
+		}
+	}
+
+	return res
+}
+
+// diffFlatMapRecords reports the differences between the flattened config maps
+// m1 and m2 as records, m1 is the left and m2 the right side of the diff.
+func diffFlatMapRecords(m1, m2 map[string]any) []configDiffRecord {
+	res := []configDiffRecord{}
 
 	allKeys := map[string]struct{}{}
 	for k := range m1 {
@@ -496,29 +808,36 @@ func diffFlatMaps(m1, m2 map[string]any) []string {
 			continue
 		}
 
+		context, _ := flatKeyContext(k)
+
 		v1, ok := m1[k]
 		if !ok {
-			res = append(res, fmt.Sprintf(">: %s\n", k))
+			res = append(res, configDiffRecord{Path: context, Kind: diffKindAdded, Right: m2[k], key: k})
 			continue
 		}
 
 		v2, ok := m2[k]
 		if !ok {
-			res = append(res, fmt.Sprintf("<: %s\n", k))
-			continue
-		}
-
-		// #TOOLS-2979 if part of logging section and is valid logging enum when compared "info" == "INFO"
-		if strings.HasPrefix(k, "logging.") && isValidLoggingEnumCompare(v1, v2) {
+			res = append(res, configDiffRecord{Path: context, Kind: diffKindRemoved, Left: v1, key: k})
 			continue
 		}
 
-		if !reflect.DeepEqual(v1, v2) {
+		if !flatValuesEqual(k, v1, v2) {
 			// Debug: print types and values for investigation
 			logger.Debugf("Diff found for key '%s': local=%v (type=%T), server=%v (type=%T)", k, v1, v1, v2, v2)
-			res = append(res, fmt.Sprintf("%s:\n\t<: %v\n\t>: %v\n", k, v1, v2))
+			res = append(res, configDiffRecord{Path: context, Kind: diffKindChanged, Left: v1, Right: v2, key: k})
 		}
 	}
 
 	return res
 }
+
+// flatValuesEqual reports whether v1 and v2 are equal values of the flat map key k.
+func flatValuesEqual(k string, v1, v2 any) bool {
+	// #TOOLS-2979 if part of logging section and is valid logging enum when compared "info" == "INFO"
+	if strings.HasPrefix(k, "logging.") && isValidLoggingEnumCompare(v1, v2) {
+		return true
+	}
+
+	return reflect.DeepEqual(v1, v2)
+}
@@ -0,0 +1,407 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	aero "github.com/aerospike/aerospike-client-go/v8"
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/aerospike/aerospike-management-lib/info"
+	"github.com/spf13/cobra"
+)
+
+// Info commands used to discover the nodes of a cluster.
+const (
+	infoCmdNode          = "node"
+	infoCmdPeersClearStd = "peers-clear-std"
+	infoCmdPeersClearAlt = "peers-clear-alt"
+	infoCmdPeersTLSStd   = "peers-tls-std"
+	infoCmdPeersTLSAlt   = "peers-tls-alt"
+)
+
+var (
+	errUnableToDiscoverNodes = errors.New("unable to discover the cluster nodes")
+	errUnableToDiffAllNodes  = errors.New("unable to generate the config of every cluster node")
+	errInvalidPeerEndpoint   = errors.New("invalid peer endpoint")
+)
+
+// clusterNode is a node of the cluster whose config is diffed.
+type clusterNode struct {
+	ID   string
+	Host *aero.Host
+}
+
+func (n clusterNode) String() string {
+	return fmt.Sprintf("%s (%s)", n.ID, n.Host)
+}
+
+// nodeConfig is the generated config of a cluster node, or the error generating it.
+type nodeConfig struct {
+	node clusterNode
+	conf *asConf.AsConfig
+	err  error
+}
+
+// nodeDiffRecord is the difference between the local config and a cluster node.
+type nodeDiffRecord struct {
+	Node        string             `json:"node"            yaml:"node"`
+	Host        string             `json:"host"            yaml:"host"`
+	Error       string             `json:"error,omitempty" yaml:"error,omitempty"`
+	Differences []configDiffRecord `json:"differences"     yaml:"differences"`
+}
+
+// nodeDivergence is a setting on which the cluster nodes differ. Values maps the
+// node ids to their value of the setting, nil for nodes that do not set it.
+type nodeDivergence struct {
+	Path   string         `json:"path"   yaml:"path"`
+	Values map[string]any `json:"values" yaml:"values"`
+}
+
+// clusterDiffReport is the result of diffing every cluster node against the local
+// config and against each other.
+type clusterDiffReport struct {
+	Local       string           `json:"local"       yaml:"local"`
+	Nodes       []nodeDiffRecord `json:"nodes"       yaml:"nodes"`
+	Divergences []nodeDivergence `json:"divergences" yaml:"divergences"`
+}
+
+// differs reports whether any node differs from the local config or from another node.
+func (r clusterDiffReport) differs() bool {
+	for _, node := range r.Nodes {
+		if len(node.Differences) > 0 {
+			return true
+		}
+	}
+
+	return len(r.Divergences) > 0
+}
+
+// failed reports whether the config of any node could not be generated.
+func (r clusterDiffReport) failed() bool {
+	for _, node := range r.Nodes {
+		if node.Error != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runClusterDiff diffs every node of the cluster that seed belongs to against the
+// local config and against each other.
+func runClusterDiff(
+	cmd *cobra.Command,
+	outFmt string,
+	rules diffRules,
+	localPath string,
+	localConf *asConf.AsConfig,
+	localFormat asConf.Format,
+	seed *aero.Host,
+	policy *aero.ClientPolicy,
+) error {
+	nodes, err := discoverClusterNodes(seed, policy)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Retrieving Aerospike configuration from %d nodes", len(nodes))
+
+	configs := generateNodeConfigs(nodes, func(host *aero.Host) (*asConf.AsConfig, error) {
+		return generateNodeConfig(host, policy, localFormat)
+	})
+
+	report := diffClusterNodes(localPath, localConf, configs, rules)
+
+	if err := renderClusterDiff(cmd.OutOrStdout(), outFmt, report, localConf, configs, localFormat); err != nil {
+		return err
+	}
+
+	if report.failed() {
+		return errors.Join(errUnableToDiffAllNodes, ErrSilent)
+	}
+
+	if report.differs() {
+		return fmt.Errorf("%w: %w", errDiffConfigsDiffer, ErrSilent)
+	}
+
+	return nil
+}
+
+// discoverClusterNodes returns the seed node and the peers it reports, sorted by node id.
+func discoverClusterNodes(seed *aero.Host, policy *aero.ClientPolicy) ([]clusterNode, error) {
+	asinfo := info.NewAsInfo(mgmtLibLogger, seed, policy)
+	defer asinfo.Close()
+
+	peersCmd := peersInfoCmd(policy)
+
+	res, err := asinfo.RequestInfo(infoCmdNode, peersCmd)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errUnableToDiscoverNodes, err)
+	}
+
+	nodes := []clusterNode{{ID: res[infoCmdNode], Host: seed}}
+	peers := info.ParseNodeEndpointList(res[peersCmd])
+
+	for _, peer := range peers.Nodes {
+		if len(peer.Endpoints) == 0 {
+			logger.Warnf("Skipping node %s, it has no %s endpoints", peer.NodeID, peersCmd)
+			continue
+		}
+
+		host, err := parsePeerEndpoint(peer.Endpoints[0], peers.DefaultPort)
+		if err != nil {
+			return nil, fmt.Errorf("%w: node %s: %w", errUnableToDiscoverNodes, peer.NodeID, err)
+		}
+
+		host.TLSName = peer.TLSName
+		nodes = append(nodes, clusterNode{ID: peer.NodeID, Host: host})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	logger.Debugf("Discovered cluster nodes %v", nodes)
+
+	return nodes, nil
+}
+
+// peersInfoCmd returns the info command listing the peer addresses that clients
+// with policy connect to.
+func peersInfoCmd(policy *aero.ClientPolicy) string {
+	switch {
+	case policy.TlsConfig != nil && policy.UseServicesAlternate:
+		return infoCmdPeersTLSAlt
+	case policy.TlsConfig != nil:
+		return infoCmdPeersTLSStd
+	case policy.UseServicesAlternate:
+		return infoCmdPeersClearAlt
+	default:
+		return infoCmdPeersClearStd
+	}
+}
+
+// parsePeerEndpoint parses a peer endpoint of the form address[:port], the port
+// defaults to defaultPort.
+func parsePeerEndpoint(endpoint string, defaultPort int) (*aero.Host, error) {
+	name, portStr, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		// the endpoint has no port
+		return aero.NewHost(strings.Trim(endpoint, "[]"), defaultPort), nil
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidPeerEndpoint, endpoint)
+	}
+
+	return aero.NewHost(name, port), nil
+}
+
+// generateNodeConfigs generates the config of every node concurrently.
+func generateNodeConfigs(
+	nodes []clusterNode,
+	generate func(host *aero.Host) (*asConf.AsConfig, error),
+) []nodeConfig {
+	res := make([]nodeConfig, len(nodes))
+
+	var wg sync.WaitGroup
+
+	for i, node := range nodes {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			logger.Debugf("Retrieving Aerospike configuration from node %s", node)
+
+			conf, err := generate(node.Host)
+			res[i] = nodeConfig{node: node, conf: conf, err: err}
+		}()
+	}
+
+	wg.Wait()
+
+	return res
+}
+
+// diffClusterNodes diffs every node config against the local config and against
+// each other, after normalizing them with rules.
+func diffClusterNodes(
+	localPath string,
+	localConf *asConf.AsConfig,
+	configs []nodeConfig,
+	rules diffRules,
+) clusterDiffReport {
+	report := clusterDiffReport{
+		Local:       localPath,
+		Nodes:       make([]nodeDiffRecord, 0, len(configs)),
+		Divergences: nodeDivergences(configs, rules),
+	}
+
+	localMap := rules.normalize(*localConf.GetFlatMap())
+
+	for _, config := range configs {
+		record := nodeDiffRecord{
+			Node:        config.node.ID,
+			Host:        config.node.Host.String(),
+			Differences: []configDiffRecord{},
+		}
+
+		if config.err != nil {
+			record.Error = config.err.Error()
+		} else {
+			record.Differences = diffFlatMapRecords(localMap, rules.normalize(*config.conf.GetFlatMap()))
+		}
+
+		report.Nodes = append(report.Nodes, record)
+	}
+
+	return report
+}
+
+// nodeDivergences returns the settings on which the successfully generated node
+// configs differ after normalizing them with rules, sorted by their flat map key.
+func nodeDivergences(configs []nodeConfig, rules diffRules) []nodeDivergence {
+	maps := map[string]map[string]any{}
+	keys := map[string]struct{}{}
+
+	for _, config := range configs {
+		if config.err != nil {
+			continue
+		}
+
+		flatMap := rules.normalize(*config.conf.GetFlatMap())
+		maps[config.node.ID] = flatMap
+
+		for key := range flatMap {
+			keys[key] = struct{}{}
+		}
+	}
+
+	keyList := make([]string, 0, len(keys))
+	for key := range keys {
+		keyList = append(keyList, key)
+	}
+
+	sort.Strings(keyList)
+
+	res := []nodeDivergence{}
+
+	for _, key := range keyList {
+		if strings.HasSuffix(key, "."+flatKeyIndex) {
+			continue
+		}
+
+		values := make(map[string]any, len(maps))
+		diverges := false
+
+		var first any
+
+		firstSet := false
+
+		for node, flatMap := range maps {
+			val, ok := flatMap[key]
+			values[node] = val
+
+			switch {
+			case !ok:
+				diverges = true
+			case !firstSet:
+				first, firstSet = val, true
+			case !flatValuesEqual(key, first, val):
+				diverges = true
+			}
+		}
+
+		if diverges {
+			context, _ := flatKeyContext(key)
+			res = append(res, nodeDivergence{Path: context, Values: values})
+		}
+	}
+
+	return res
+}
+
+// renderClusterDiff writes the cluster diff report to w in outFmt. The unified
+// format shows the unified diff of the local config against each node.
+func renderClusterDiff(
+	w io.Writer,
+	outFmt string,
+	report clusterDiffReport,
+	localConf *asConf.AsConfig,
+	configs []nodeConfig,
+	format asConf.Format,
+) error {
+	switch outFmt {
+	case outputFormatText:
+		return renderClusterDiffText(w, report)
+	case outputFormatUnified:
+		for _, config := range configs {
+			if config.err != nil {
+				continue
+			}
+
+			nodeReport := configDiffReport{Left: report.Local, Right: config.node.String()}
+			if err := renderConfigDiff(w, outFmt, nodeReport, localConf, config.conf, format); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		return renderStructured(w, outFmt, report)
+	}
+}
+
+// renderClusterDiffText writes the cluster diff report to w as text, the node
+// differences use the diffFlatMaps text format.
+func renderClusterDiffText(w io.Writer, report clusterDiffReport) error {
+	var sb strings.Builder
+
+	for _, node := range report.Nodes {
+		switch {
+		case node.Error != "":
+			fmt.Fprintf(&sb, "Unable to generate the config of node %s (%s): %s\n", node.Node, node.Host, node.Error)
+		case len(node.Differences) > 0:
+			fmt.Fprintf(&sb,
+				"Differences shown from %s to node %s (%s), '<' are from local file, '>' are from node.\n",
+				report.Local, node.Node, node.Host)
+			fmt.Fprintf(&sb, "%s\n", strings.Join(formatFlatMapDiffs(node.Differences), ""))
+		}
+	}
+
+	if len(report.Divergences) > 0 {
+		fmt.Fprintf(&sb, "Nodes diverge on %d settings:\n", len(report.Divergences))
+
+		for _, divergence := range report.Divergences {
+			fmt.Fprintf(&sb, "%s:\n", divergence.Path)
+
+			nodes := make([]string, 0, len(divergence.Values))
+			for node := range divergence.Values {
+				nodes = append(nodes, node)
+			}
+
+			sort.Strings(nodes)
+
+			for _, node := range nodes {
+				val := divergence.Values[node]
+				if val == nil {
+					val = "<not set>"
+				}
+
+				fmt.Fprintf(&sb, "\t%s: %v\n", node, val)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
@@ -0,0 +1,179 @@
+//go:build unit
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"strings"
+	"testing"
+
+	aero "github.com/aerospike/aerospike-client-go/v8"
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+)
+
+func testNodeConfig(t *testing.T, id, src string) nodeConfig {
+	t.Helper()
+
+	c, err := asConf.NewASConfigFromBytes(mgmtLibLogger, []byte(src), asConf.AeroConfig)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	return nodeConfig{node: clusterNode{ID: id, Host: aero.NewHost("10.0.0."+id, 3000)}, conf: c}
+}
+
+func TestDiffClusterNodes(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	local := testNodeConfig(t, "local", "service {\n\tproto-fd-max 15000\n}\n")
+
+	configs := []nodeConfig{
+		testNodeConfig(t, "1", "service {\n\tproto-fd-max 15000\n}\n"),
+		testNodeConfig(t, "2", "service {\n\tproto-fd-max 20000\n}\n"),
+		testNodeConfig(t, "3", "service {\n\tproto-fd-max 15000\n\tcluster-name cl1\n}\n"),
+		{node: clusterNode{ID: "4", Host: aero.NewHost("10.0.0.4", 3000)}, err: errUnableToGenerateConfigFromServer},
+	}
+
+	report := diffClusterNodes("aerospike.conf", local.conf, configs, diffRules{})
+
+	if len(report.Nodes) != 4 {
+		t.Fatalf("diffClusterNodes() nodes = %+v, want 4 nodes", report.Nodes)
+	}
+
+	if len(report.Nodes[0].Differences) != 0 {
+		t.Errorf("diffClusterNodes() node 1 differences = %+v, want none", report.Nodes[0].Differences)
+	}
+
+	if len(report.Nodes[1].Differences) != 1 || report.Nodes[1].Differences[0].Path != "service.proto-fd-max" {
+		t.Errorf("diffClusterNodes() node 2 differences = %+v, want proto-fd-max", report.Nodes[1].Differences)
+	}
+
+	if report.Nodes[3].Error == "" {
+		t.Errorf("diffClusterNodes() node 4 = %+v, want an error", report.Nodes[3])
+	}
+
+	if !report.differs() || !report.failed() {
+		t.Errorf("diffClusterNodes() differs = %v, failed = %v, want both", report.differs(), report.failed())
+	}
+
+	want := []nodeDivergence{
+		{Path: "service.cluster-name", Values: map[string]any{"1": nil, "2": nil, "3": "cl1"}},
+		{Path: "service.proto-fd-max", Values: map[string]any{"1": uint64(15000), "2": uint64(20000), "3": uint64(15000)}},
+	}
+
+	if len(report.Divergences) != len(want) {
+		t.Fatalf("diffClusterNodes() divergences = %+v, want %+v", report.Divergences, want)
+	}
+
+	for i, w := range want {
+		got := report.Divergences[i]
+		if got.Path != w.Path || len(got.Values) != len(w.Values) {
+			t.Errorf("diffClusterNodes() divergence %d = %+v, want %+v", i, got, w)
+			continue
+		}
+
+		for node, val := range w.Values {
+			if got.Values[node] != val {
+				t.Errorf("diffClusterNodes() divergence %s node %s = %v (%T), want %v", w.Path, node, got.Values[node], got.Values[node], val)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := renderClusterDiff(&buf, outputFormatText, report, local.conf, configs, asConf.AeroConfig); err != nil {
+		t.Fatalf("renderClusterDiff() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"Differences shown from aerospike.conf to node 2 (10.0.0.2:3000)",
+		"Unable to generate the config of node 4 (10.0.0.4:3000)",
+		"Nodes diverge on 2 settings:\nservice.cluster-name:\n\t1: <not set>\n\t2: <not set>\n\t3: cl1\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("renderClusterDiff() = %s, want it to contain %q", buf.String(), want)
+		}
+	}
+}
+
+func TestGenerateNodeConfigs(t *testing.T) {
+	nodes := []clusterNode{
+		{ID: "1", Host: aero.NewHost("10.0.0.1", 3000)},
+		{ID: "2", Host: aero.NewHost("10.0.0.2", 3000)},
+	}
+
+	configs := generateNodeConfigs(nodes, func(host *aero.Host) (*asConf.AsConfig, error) {
+		if host.Name == "10.0.0.2" {
+			return nil, errUnableToGenerateConfigFromServer
+		}
+
+		return asConf.NewASConfigFromBytes(mgmtLibLogger, []byte("service {\n}\n"), asConf.AeroConfig)
+	})
+
+	if configs[0].node.ID != "1" || configs[0].err != nil || configs[0].conf == nil {
+		t.Errorf("generateNodeConfigs()[0] = %+v, want the config of node 1", configs[0])
+	}
+
+	if configs[1].node.ID != "2" || !errors.Is(configs[1].err, errUnableToGenerateConfigFromServer) {
+		t.Errorf("generateNodeConfigs()[1] = %+v, want the error of node 2", configs[1])
+	}
+}
+
+func TestParsePeerEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		wantName string
+		wantPort int
+		wantErr  bool
+	}{
+		{endpoint: "10.0.0.1:3100", wantName: "10.0.0.1", wantPort: 3100},
+		{endpoint: "10.0.0.1", wantName: "10.0.0.1", wantPort: 3000},
+		{endpoint: "[2001:db8::1]:3100", wantName: "2001:db8::1", wantPort: 3100},
+		{endpoint: "[2001:db8::1]", wantName: "2001:db8::1", wantPort: 3000},
+		{endpoint: "10.0.0.1:port", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		host, err := parsePeerEndpoint(tt.endpoint, 3000)
+		if tt.wantErr {
+			if !errors.Is(err, errInvalidPeerEndpoint) {
+				t.Errorf("parsePeerEndpoint(%s) error = %v, want %v", tt.endpoint, err, errInvalidPeerEndpoint)
+			}
+
+			continue
+		}
+
+		if err != nil || host.Name != tt.wantName || host.Port != tt.wantPort {
+			t.Errorf("parsePeerEndpoint(%s) = %v, %v, want %s:%d", tt.endpoint, host, err, tt.wantName, tt.wantPort)
+		}
+	}
+}
+
+func TestPeersInfoCmd(t *testing.T) {
+	tests := []struct {
+		tls       bool
+		alternate bool
+		want      string
+	}{
+		{want: infoCmdPeersClearStd},
+		{alternate: true, want: infoCmdPeersClearAlt},
+		{tls: true, want: infoCmdPeersTLSStd},
+		{tls: true, alternate: true, want: infoCmdPeersTLSAlt},
+	}
+
+	for _, tt := range tests {
+		policy := aero.NewClientPolicy()
+		policy.UseServicesAlternate = tt.alternate
+
+		if tt.tls {
+			policy.TlsConfig = &tls.Config{} //nolint:gosec // not used to connect
+		}
+
+		if got := peersInfoCmd(policy); got != tt.want {
+			t.Errorf("peersInfoCmd(tls=%v, alternate=%v) = %s, want %s", tt.tls, tt.alternate, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"errors"
+	"maps"
+	"reflect"
+	"strings"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/spf13/cobra"
+
+	"github.com/aerospike/asconfig/schema"
+)
+
+// Sides of a config diff whose value is the implicit schema default.
+const (
+	implicitLeft  = "left"
+	implicitRight = "right"
+)
+
+// implicitSuffix marks implicit default values in the text diff format.
+const implicitSuffix = " (default)"
+
+var errDiffEffectiveAllNodes = errors.New("--effective can not be used with --all-nodes")
+
+// effectiveFlatMaps is a pair of flattened config maps filled with the schema
+// defaults of the settings they omit, and the keys that were filled.
+type effectiveFlatMaps struct {
+	left, right                 map[string]any
+	leftImplicit, rightImplicit map[string]struct{}
+}
+
+// getEffectiveSchema returns the schema used to fill in defaults when the
+// --effective flag is set, or nil. The server version is read from the
+// --aerospike-version flag or from the metadata of the first source that has it.
+func getEffectiveSchema(cmd *cobra.Command, sources ...[]byte) (map[string]any, error) {
+	effective, err := cmd.Flags().GetBool("effective")
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Processing flag effective value=%v", effective)
+
+	if !effective {
+		return nil, nil
+	}
+
+	var version string
+
+	for _, src := range sources {
+		version, err = getSourceVersion(cmd, src)
+		if err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	schemaMap, err := schema.NewSchemaMap()
+	if err != nil {
+		return nil, err
+	}
+
+	return loadSchema(schemaMap, version)
+}
+
+// newEffectiveFlatMaps fills left and right with the defaults in schemaRoot of
+// the settings they omit. Defaults are filled in for the sections that either
+// side configures, list items such as namespaces are only filled in on the side
+// that has them.
+func newEffectiveFlatMaps(schemaRoot map[string]any, left, right map[string]any) effectiveFlatMaps {
+	containers := flatMapContainers(left)
+	for container := range flatMapContainers(right) {
+		containers[container] = struct{}{}
+	}
+
+	res := effectiveFlatMaps{}
+	res.left, res.leftImplicit = fillDefaults(schemaRoot, containers, left, right)
+	res.right, res.rightImplicit = fillDefaults(schemaRoot, containers, right, left)
+
+	return res
+}
+
+// markImplicit sets the implicit side of the records whose value on that side is
+// a filled in default.
+func (m effectiveFlatMaps) markImplicit(records []configDiffRecord) {
+	for i := range records {
+		if _, ok := m.leftImplicit[records[i].key]; ok {
+			records[i].Implicit = implicitLeft
+		}
+
+		if _, ok := m.rightImplicit[records[i].key]; ok {
+			records[i].Implicit = implicitRight
+		}
+	}
+}
+
+// flatMapContainers returns the keys of the sections and list items that hold
+// the settings of flatMap, and their ancestors.
+func flatMapContainers(flatMap map[string]any) map[string]struct{} {
+	res := map[string]struct{}{}
+
+	for key := range flatMap {
+		parts := asConf.SplitKey(mgmtLibLogger, key, ".")
+
+		for i := 1; i < len(parts); i++ {
+			res[strings.Join(parts[:i], ".")] = struct{}{}
+		}
+	}
+
+	return res
+}
+
+// fillDefaults returns a copy of flatMap with the scalar defaults of the settings
+// of containers that it omits, and the keys that were filled in. Numeric defaults
+// take the type of the value in other so that equal values compare equal.
+func fillDefaults(
+	schemaRoot map[string]any,
+	containers map[string]struct{},
+	flatMap, other map[string]any,
+) (filled map[string]any, implicit map[string]struct{}) {
+	filled = make(map[string]any, len(flatMap))
+	for key, val := range flatMap {
+		filled[key] = val
+	}
+
+	implicit = map[string]struct{}{}
+	own := flatMapContainers(flatMap)
+
+	for container := range containers {
+		if !hasListItems(container, own) {
+			continue
+		}
+
+		_, path := flatKeyContext(container)
+
+		prop, ok := schemaPropertyAt(schemaRoot, path)
+		if !ok {
+			continue
+		}
+
+		// list sections are filled in per item
+		items, isList := schemaItems(prop.node, prop.pointer)
+		if isList != strings.HasSuffix(container, "}") {
+			continue
+		}
+
+		if isList {
+			prop = items
+		}
+
+		typeVal, _ := flatMap[container+"."+keyTypeField].(string)
+
+		for name, setting := range sectionProperties(prop.node, typeVal) {
+			def, ok := setting.node[schemaDefaultKeyword]
+			if !ok || def == nil {
+				continue
+			}
+
+			switch def.(type) {
+			case map[string]any, []any:
+				continue
+			}
+
+			key := container + "." + name
+			if _, ok := flatMap[key]; ok {
+				continue
+			}
+
+			filled[key] = convertDefault(def, other[key])
+			implicit[key] = struct{}{}
+		}
+	}
+
+	return filled, implicit
+}
+
+// hasListItems reports whether every list item in the container key, such as
+// namespaces.{test}, is one of the containers in own.
+func hasListItems(container string, own map[string]struct{}) bool {
+	parts := asConf.SplitKey(mgmtLibLogger, container, ".")
+
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "{") {
+			continue
+		}
+
+		if _, ok := own[strings.Join(parts[:i+1], ".")]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sectionProperties returns the property definitions of a config section. The
+// alternatives of oneOf, anyOf and allOf are only used if they describe sections
+// of typeVal, the type of a typed section such as storage-engine.
+func sectionProperties(node map[string]any, typeVal string) map[string]schemaProperty {
+	own := maps.Clone(node)
+	for _, keyword := range schemaCombinators {
+		delete(own, keyword)
+	}
+
+	res := schemaProperties(own, "")
+
+	if typeVal == "" {
+		return res
+	}
+
+	for _, alt := range matchingAlternatives(node, map[string]any{keyTypeField: typeVal}) {
+		for key, prop := range sectionProperties(alt, typeVal) {
+			if _, ok := res[key]; !ok {
+				res[key] = prop
+			}
+		}
+	}
+
+	return res
+}
+
+// convertDefault converts a json number default to the numeric type of like.
+// Other defaults, and defaults that don't fit the type, are returned unchanged.
+func convertDefault(def, like any) any {
+	num, ok := def.(float64)
+	if !ok || like == nil {
+		return def
+	}
+
+	likeType := reflect.TypeOf(like)
+
+	switch likeType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if num == float64(int64(num)) {
+			return reflect.ValueOf(int64(num)).Convert(likeType).Interface()
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if num >= 0 && num == float64(uint64(num)) {
+			return reflect.ValueOf(uint64(num)).Convert(likeType).Interface()
+		}
+	case reflect.Float32, reflect.Float64:
+		return reflect.ValueOf(num).Convert(likeType).Interface()
+	}
+
+	return def
+}
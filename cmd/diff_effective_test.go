@@ -0,0 +1,138 @@
+//go:build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+)
+
+func TestDiffConfigFlatMapsEffective(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	var schemaRoot map[string]any
+	if err := json.Unmarshal([]byte(testImpactSchemaLower), &schemaRoot); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	left, err := asConf.NewASConfigFromBytes(mgmtLibLogger, []byte(
+		"service {\n\tproto-fd-max 15000\n}\n"+
+			"namespace test {\n\treplication-factor 2\n\tstorage-engine memory\n}\n"+
+			"namespace bar {\n\tstorage-engine memory\n}\n"), asConf.AeroConfig)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	right, err := asConf.NewASConfigFromBytes(mgmtLibLogger, []byte(
+		"service {\n\told-setting 1\n}\n"+
+			"namespace test {\n\tstorage-engine memory\n}\n"), asConf.AeroConfig)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	got := diffConfigFlatMaps(*left.GetFlatMap(), *right.GetFlatMap(), diffRules{}, schemaRoot)
+
+	want := []configDiffRecord{
+		{Path: "namespaces.bar.high-water-disk-pct", Kind: diffKindRemoved, Implicit: implicitLeft},
+		{Path: "namespaces.bar.name", Kind: diffKindRemoved},
+		{Path: "namespaces.bar.replication-factor", Kind: diffKindRemoved, Implicit: implicitLeft},
+		{Path: "namespaces.bar.storage-engine.type", Kind: diffKindRemoved},
+		{Path: "namespaces.test.replication-factor", Kind: diffKindChanged, Implicit: implicitRight},
+		{Path: "service.old-setting", Kind: diffKindChanged, Implicit: implicitLeft},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("diffConfigFlatMaps() = %+v, want %+v", got, want)
+	}
+
+	for i, w := range want {
+		if got[i].Path != w.Path || got[i].Kind != w.Kind || got[i].Implicit != w.Implicit {
+			t.Errorf("diffConfigFlatMaps()[%d] = %+v, want %+v", i, got[i], w)
+		}
+	}
+
+	if got[4].Right != int64(1) {
+		t.Errorf("diffConfigFlatMaps() implicit replication-factor = %v (%T), want the int64 default 1", got[4].Right, got[4].Right)
+	}
+
+	diffs := formatFlatMapDiffs(got[5:])
+	if wantText := "service.old-setting:\n\t<: 0 (default)\n\t>: 1\n"; len(diffs) != 1 || diffs[0] != wantText {
+		t.Errorf("formatFlatMapDiffs() = %q, want %q", diffs, wantText)
+	}
+}
+
+func TestConvertDefault(t *testing.T) {
+	tests := []struct {
+		def  any
+		like any
+		want any
+	}{
+		{def: float64(15000), like: uint64(20000), want: uint64(15000)},
+		{def: float64(-1), like: int64(1), want: int64(-1)},
+		{def: float64(-1), like: uint64(1), want: float64(-1)},
+		{def: float64(1.5), like: 2, want: float64(1.5)},
+		{def: float64(2), like: nil, want: float64(2)},
+		{def: "none", like: "all", want: "none"},
+	}
+
+	for _, tt := range tests {
+		if got := convertDefault(tt.def, tt.like); got != tt.want {
+			t.Errorf("convertDefault(%v, %T) = %v (%T), want %v (%T)", tt.def, tt.like, got, got, tt.want, tt.want)
+		}
+	}
+}
+
+func TestRunEFileDiffEffective(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	dir := t.TempDir()
+	path1 := filepath.Join(dir, "aerospike1.conf")
+	path2 := filepath.Join(dir, "aerospike2.conf")
+
+	if err := os.WriteFile(path1, []byte("service {\n\tcluster-name cl1\n\tproto-fd-max 15000\n}\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if err := os.WriteFile(path2, []byte("service {\n\tcluster-name cl1\n}\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		flags   []string
+		wantErr error
+	}{
+		{name: "not effective", flags: []string{"--output-format", "json"}, wantErr: errDiffConfigsDiffer},
+		{name: "effective", flags: []string{"--output-format", "json", "--effective", "--aerospike-version", "7.0.0"}},
+		{name: "missing version", flags: []string{"--effective"}, wantErr: errMissingAerospikeVersion},
+		{
+			name:    "unified",
+			flags:   []string{"--output-format", "unified", "--effective", "--aerospike-version", "7.0.0"},
+			wantErr: errDiffRulesUnifiedFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newDiffFilesCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			cmd.ParseFlags(tt.flags)
+			err := cmd.RunE(cmd, []string{path1, path2})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunE() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+)
+
+// Kinds of upgrade impact, in the order they are reported.
+const (
+	impactRemoved        = "removed"
+	impactRenamed        = "renamed"
+	impactRetyped        = "retyped"
+	impactDefaultChanged = "default-changed"
+)
+
+var impactKindOrder = []string{impactRemoved, impactRenamed, impactRetyped, impactDefaultChanged}
+
+// Schema keywords whose changes affect configured settings.
+const (
+	schemaTypeKeyword    = "type"
+	schemaDefaultKeyword = "default"
+)
+
+// upgradeImpact is a change between two server versions to a setting that a
+// config file uses. Path is the setting's config path without list item names,
+// UsedBy lists the name resolved contexts in the config that use it.
+type upgradeImpact struct {
+	Kind   string   `json:"kind"             yaml:"kind"`
+	Path   string   `json:"path"             yaml:"path"`
+	Target string   `json:"target,omitempty" yaml:"target,omitempty"`
+	Old    any      `json:"old"              yaml:"old"`
+	New    any      `json:"new"              yaml:"new"`
+	UsedBy []string `json:"used-by"          yaml:"used-by"`
+}
+
+func (i upgradeImpact) String() string {
+	switch i.Kind {
+	case impactRenamed:
+		return fmt.Sprintf("%s: %s -> %s", i.Kind, i.Path, i.Target)
+	case impactRetyped, impactDefaultChanged:
+		return fmt.Sprintf("%s: %s: %v -> %v", i.Kind, i.Path, impactValueString(i.Old), impactValueString(i.New))
+	default:
+		return fmt.Sprintf("%s: %s", i.Kind, i.Path)
+	}
+}
+
+// impactValueString formats a schema value, which is absent when nil.
+func impactValueString(val any) string {
+	if val == nil {
+		return "<none>"
+	}
+
+	return fmt.Sprintf("%v", val)
+}
+
+// upgradeImpactReport is the upgrade impact of a config file between two versions.
+type upgradeImpactReport struct {
+	Source       string          `json:"source"        yaml:"source"`
+	LowerVersion string          `json:"lower-version" yaml:"lower-version"`
+	UpperVersion string          `json:"upper-version" yaml:"upper-version"`
+	Impacts      []upgradeImpact `json:"impacts"       yaml:"impacts"`
+}
+
+// upgradeImpacts intersects the schema changes in summary with the settings that
+// asconfig uses and returns the changes that affect the config.
+func upgradeImpacts(
+	asconfig *asConf.AsConfig,
+	summary ChangeSummary,
+	schemaLower, schemaUpper map[string]any,
+) ([]upgradeImpact, error) {
+	used := configUsedPaths(asconfig)
+
+	renames, err := knownRenames(summary.LowerVersion, summary.UpperVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := make([]string, 0, len(summary.Sections))
+	for section := range summary.Sections {
+		sections = append(sections, section)
+	}
+
+	sort.Strings(sections)
+
+	impacts := map[string]upgradeImpact{}
+
+	for _, section := range sections {
+		changes := summary.Sections[section]
+
+		for _, change := range slices.Concat(changes.Removals, changes.Additions, changes.Modifications) {
+			path, rest := schemaChangeTarget(change.Path)
+			if path == "" {
+				continue
+			}
+
+			var keyword string
+			if len(rest) > 0 {
+				keyword = rest[0]
+			}
+
+			switch keyword {
+			case "":
+				if change.Type == Removal {
+					addRemovalImpact(impacts, used, path, renames, schemaUpper)
+				} else {
+					addDefinitionImpacts(impacts, used, path, schemaLower, schemaUpper)
+				}
+			case schemaTypeKeyword, schemaDefaultKeyword:
+				addDefinitionImpacts(impacts, used, path, schemaLower, schemaUpper)
+			}
+		}
+	}
+
+	res := make([]upgradeImpact, 0, len(impacts))
+	for _, impact := range impacts {
+		res = append(res, impact)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		ki := slices.Index(impactKindOrder, res[i].Kind)
+		kj := slices.Index(impactKindOrder, res[j].Kind)
+
+		if ki != kj {
+			return ki < kj
+		}
+
+		return res[i].Path < res[j].Path
+	})
+
+	return res, nil
+}
+
+// addRemovalImpact records the removal of the setting or section at path if the
+// config uses it and the upper schema no longer defines it.
+func addRemovalImpact(
+	impacts map[string]upgradeImpact,
+	used map[string][]string,
+	path string,
+	renames map[string]string,
+	schemaUpper map[string]any,
+) {
+	// the setting may only have moved between alternatives of a section
+	if _, ok := schemaPropertyAt(schemaUpper, path); ok {
+		return
+	}
+
+	var usedBy []string
+
+	for usedPath, contexts := range used {
+		if usedPath == path || strings.HasPrefix(usedPath, path+".") {
+			usedBy = append(usedBy, contexts...)
+		}
+	}
+
+	if len(usedBy) == 0 {
+		return
+	}
+
+	sort.Strings(usedBy)
+
+	impact := upgradeImpact{Kind: impactRemoved, Path: path, UsedBy: usedBy}
+
+	if target, ok := renames[path]; ok {
+		if _, ok := schemaPropertyAt(schemaUpper, target); ok {
+			impact.Kind = impactRenamed
+			impact.Target = target
+		}
+	}
+
+	impacts[impact.Kind+":"+path] = impact
+}
+
+// addDefinitionImpacts records changes to the type and default of the setting
+// at path if the config uses it.
+func addDefinitionImpacts(
+	impacts map[string]upgradeImpact,
+	used map[string][]string,
+	path string,
+	schemaLower, schemaUpper map[string]any,
+) {
+	usedBy, ok := used[path]
+	if !ok {
+		return
+	}
+
+	lower, okLower := schemaPropertyAt(schemaLower, path)
+	upper, okUpper := schemaPropertyAt(schemaUpper, path)
+
+	if !okLower || !okUpper {
+		return
+	}
+
+	checks := []struct {
+		kind    string
+		keyword string
+	}{
+		{kind: impactRetyped, keyword: schemaTypeKeyword},
+		{kind: impactDefaultChanged, keyword: schemaDefaultKeyword},
+	}
+
+	for _, check := range checks {
+		oldVal := lower.node[check.keyword]
+		newVal := upper.node[check.keyword]
+
+		if reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+
+		impacts[check.kind+":"+path] = upgradeImpact{
+			Kind:   check.kind,
+			Path:   path,
+			Old:    oldVal,
+			New:    newVal,
+			UsedBy: usedBy,
+		}
+	}
+}
+
+// schemaChangeTarget returns the config path of the setting that the schema
+// change at pointer affects, and the pointer segments within the definition of
+// that setting which changed. The segments are empty when the setting itself
+// was changed.
+func schemaChangeTarget(pointer string) (path string, rest []string) {
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+
+	var names []string
+
+	restIdx := 0
+
+segments:
+	for i := 0; i < len(segments); i++ {
+		switch segment := segments[i]; {
+		case segment == propertiesField && i+1 < len(segments):
+			i++
+			names = append(names, unescapeJSONPointer(segments[i]))
+			restIdx = i + 1
+		case segment == itemsField:
+		case slices.Contains(schemaCombinators, segment) && i+1 < len(segments) && isNumeric(segments[i+1]):
+			i++
+		default:
+			break segments
+		}
+	}
+
+	return strings.Join(names, "."), segments[restIdx:]
+}
+
+// unescapeJSONPointer decodes a json pointer reference token.
+func unescapeJSONPointer(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
+
+// configUsedPaths returns the config paths, without list item names, of the
+// settings that asconfig uses, mapped to the name resolved contexts using them.
+func configUsedPaths(asconfig *asConf.AsConfig) map[string][]string {
+	res := map[string][]string{}
+
+	for key := range *asconfig.GetFlatMap() {
+		context, path := flatKeyContext(key)
+
+		base := path[strings.LastIndex(path, ".")+1:]
+		if path == "" || base == flatKeyIndex || base == keyNameField {
+			continue
+		}
+
+		res[path] = append(res[path], context)
+	}
+
+	for _, contexts := range res {
+		sort.Strings(contexts)
+	}
+
+	return res
+}
+
+// knownRenames returns the config paths of settings renamed between the
+// versions from and to, mapped to their new paths.
+func knownRenames(from, to string) (map[string]string, error) {
+	res := map[string]string{}
+
+	for _, step := range versionMigrations {
+		applies, err := migrationApplies(step.version, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		if !applies {
+			continue
+		}
+
+		for _, rename := range step.renames {
+			res["namespaces."+rename.from] = "namespaces." + rename.to
+		}
+	}
+
+	return res, nil
+}
+
+// filterImpacts returns the impacts on settings in the given top level sections.
+func filterImpacts(impacts []upgradeImpact, sections map[string]struct{}) []upgradeImpact {
+	if len(sections) == 0 {
+		return impacts
+	}
+
+	var res []upgradeImpact
+
+	for _, impact := range impacts {
+		section, _, _ := strings.Cut(impact.Path, ".")
+		if _, ok := sections[section]; ok {
+			res = append(res, impact)
+		}
+	}
+
+	return res
+}
+
+// renderUpgradeImpact writes the upgrade impact report to w in the outFmt format.
+func renderUpgradeImpact(w io.Writer, outFmt string, report upgradeImpactReport) error {
+	switch outFmt {
+	case outputFormatText:
+		return renderUpgradeImpactText(w, report)
+	case outputFormatMarkdown:
+		return renderUpgradeImpactMarkdown(w, report)
+	default:
+		return renderStructured(w, outFmt, report)
+	}
+}
+
+// renderUpgradeImpactText writes the upgrade impact report to w as text.
+func renderUpgradeImpactText(w io.Writer, report upgradeImpactReport) error {
+	if len(report.Impacts) == 0 {
+		_, err := fmt.Fprintf(w, "No settings in %s are affected by upgrading from %s to %s\n",
+			report.Source, report.LowerVersion, report.UpperVersion)
+
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "Upgrading %s from %s to %s affects %d settings\n",
+		report.Source, report.LowerVersion, report.UpperVersion, len(report.Impacts)); err != nil {
+		return err
+	}
+
+	for _, impact := range report.Impacts {
+		if _, err := fmt.Fprintf(w, "\t- %s\n\t\tused by: %s\n", impact, strings.Join(impact.UsedBy, ", ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderUpgradeImpactMarkdown writes the upgrade impact report to w as a markdown table.
+func renderUpgradeImpactMarkdown(w io.Writer, report upgradeImpactReport) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Upgrade impact of %s from %s to %s\n\n",
+		report.Source, report.LowerVersion, report.UpperVersion)
+	sb.WriteString("| Impact | Path | Old | New | Used by |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, impact := range report.Impacts {
+		oldVal, newVal := markdownValue(impact.Old), markdownValue(impact.New)
+		if impact.Kind == impactRenamed {
+			newVal = markdownCell(impact.Target)
+		}
+
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", impact.Kind, markdownCell(impact.Path),
+			oldVal, newVal, markdownCell(strings.Join(impact.UsedBy, ", ")))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
@@ -0,0 +1,262 @@
+//go:build unit
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+)
+
+const testImpactSchemaLower = `{
+	"type": "object",
+	"properties": {
+		"service": {
+			"type": "object",
+			"properties": {
+				"proto-fd-max": {"type": "integer", "default": 15000},
+				"debug-allocations": {"type": "boolean", "default": false},
+				"old-setting": {"type": "integer", "default": 0}
+			}
+		},
+		"namespaces": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"replication-factor": {"type": "integer", "default": 1},
+					"memory-size": {"type": "integer"},
+					"high-water-disk-pct": {"type": "integer", "default": 0},
+					"storage-engine": {
+						"oneOf": [
+							{"type": "object", "properties": {"type": {"enum": ["memory"]}}},
+							{"type": "object", "properties": {
+								"type": {"enum": ["device"]},
+								"files": {"type": "array"},
+								"max-used-pct": {"type": "integer"}
+							}}
+						]
+					}
+				}
+			}
+		}
+	}
+}`
+
+const testImpactSchemaUpper = `{
+	"type": "object",
+	"properties": {
+		"service": {
+			"type": "object",
+			"properties": {
+				"proto-fd-max": {"type": "integer", "default": 15000},
+				"debug-allocations": {"type": "string", "default": "none"}
+			}
+		},
+		"namespaces": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"replication-factor": {"type": "integer", "default": 2},
+					"storage-engine": {
+						"oneOf": [
+							{"type": "object", "properties": {"type": {"enum": ["memory"]}, "data-size": {"type": "integer"}}},
+							{"type": "object", "properties": {
+								"type": {"enum": ["device"]},
+								"files": {"type": "array"},
+								"evict-used-pct": {"type": "integer"}
+							}}
+						]
+					}
+				}
+			}
+		}
+	}
+}`
+
+const testImpactConf = `
+service {
+	proto-fd-max 20000
+	debug-allocations true
+	old-setting 1
+}
+namespace test {
+	replication-factor 2
+	high-water-disk-pct 50
+	storage-engine device {
+		file /opt/test.dat
+		max-used-pct 70
+	}
+}
+namespace bar {
+	memory-size 4G
+	storage-engine memory
+}
+`
+
+func TestUpgradeImpacts(t *testing.T) {
+	var schemaLower, schemaUpper map[string]any
+	if err := json.Unmarshal([]byte(testImpactSchemaLower), &schemaLower); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	if err := json.Unmarshal([]byte(testImpactSchemaUpper), &schemaUpper); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	summary, err := compareSchemas(schemaLower, schemaUpper, "6.4.0", "7.0.0")
+	if err != nil {
+		t.Fatalf("compareSchemas() error = %v", err)
+	}
+
+	asconfig, err := asConf.NewASConfigFromBytes(mgmtLibLogger, []byte(testImpactConf), asConf.AeroConfig)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	got, err := upgradeImpacts(asconfig, summary, schemaLower, schemaUpper)
+	if err != nil {
+		t.Fatalf("upgradeImpacts() error = %v", err)
+	}
+
+	want := []upgradeImpact{
+		{Kind: impactRemoved, Path: "namespaces.memory-size", UsedBy: []string{"namespaces.bar.memory-size"}},
+		{
+			Kind:   impactRemoved,
+			Path:   "namespaces.storage-engine.max-used-pct",
+			UsedBy: []string{"namespaces.test.storage-engine.max-used-pct"},
+		},
+		{Kind: impactRemoved, Path: "service.old-setting", UsedBy: []string{"service.old-setting"}},
+		{
+			Kind:   impactRenamed,
+			Path:   "namespaces.high-water-disk-pct",
+			Target: "namespaces.storage-engine.evict-used-pct",
+			UsedBy: []string{"namespaces.test.high-water-disk-pct"},
+		},
+		{Kind: impactRetyped, Path: "service.debug-allocations", Old: "boolean", New: "string"},
+		{Kind: impactDefaultChanged, Path: "namespaces.replication-factor", Old: float64(1), New: float64(2)},
+		{Kind: impactDefaultChanged, Path: "service.debug-allocations", Old: false, New: "none"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("upgradeImpacts() = %+v, want %d impacts", got, len(want))
+	}
+
+	for i, w := range want {
+		if got[i].Kind != w.Kind || got[i].Path != w.Path || got[i].Target != w.Target ||
+			got[i].Old != w.Old || got[i].New != w.New ||
+			(w.UsedBy != nil && strings.Join(got[i].UsedBy, ",") != strings.Join(w.UsedBy, ",")) {
+			t.Errorf("upgradeImpacts()[%d] = %+v, want %+v", i, got[i], w)
+		}
+	}
+
+	filtered := filterImpacts(got, map[string]struct{}{"service": {}})
+	if len(filtered) != 3 {
+		t.Errorf("filterImpacts() = %+v, want the 3 service impacts", filtered)
+	}
+}
+
+func TestSchemaChangeTarget(t *testing.T) {
+	tests := []struct {
+		pointer  string
+		wantPath string
+		wantRest string
+	}{
+		{pointer: "/properties/service/properties/proto-fd-max", wantPath: "service.proto-fd-max"},
+		{
+			pointer:  "/properties/namespaces/items/properties/replication-factor/default",
+			wantPath: "namespaces.replication-factor",
+			wantRest: "default",
+		},
+		{
+			pointer:  "/properties/namespaces/items/properties/storage-engine/oneOf/1/properties/files",
+			wantPath: "namespaces.storage-engine.files",
+		},
+		{
+			pointer:  "/properties/namespaces/items/properties/storage-engine/oneOf/1",
+			wantPath: "namespaces.storage-engine",
+			wantRest: "oneOf/1",
+		},
+		{pointer: "/properties/logging/items/properties/a~1b/type", wantPath: "logging.a/b", wantRest: "type"},
+	}
+
+	for _, tt := range tests {
+		path, rest := schemaChangeTarget(tt.pointer)
+		if path != tt.wantPath || strings.Join(rest, "/") != tt.wantRest {
+			t.Errorf("schemaChangeTarget(%s) = %s, %v, want %s, %s",
+				tt.pointer, path, rest, tt.wantPath, tt.wantRest)
+		}
+	}
+}
+
+func TestRenderUpgradeImpact(t *testing.T) {
+	report := upgradeImpactReport{
+		Source:       "aerospike.conf",
+		LowerVersion: "6.4.0",
+		UpperVersion: "7.0.0",
+		Impacts: []upgradeImpact{{
+			Kind:   impactRenamed,
+			Path:   "namespaces.high-water-disk-pct",
+			Target: "namespaces.storage-engine.evict-used-pct",
+			UsedBy: []string{"namespaces.test.high-water-disk-pct"},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := renderUpgradeImpact(&buf, outputFormatText, report); err != nil {
+		t.Fatalf("renderUpgradeImpact() error = %v", err)
+	}
+
+	want := "Upgrading aerospike.conf from 6.4.0 to 7.0.0 affects 1 settings\n" +
+		"\t- renamed: namespaces.high-water-disk-pct -> namespaces.storage-engine.evict-used-pct\n" +
+		"\t\tused by: namespaces.test.high-water-disk-pct\n"
+	if buf.String() != want {
+		t.Errorf("renderUpgradeImpact() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRunEVersionsDiffImpact(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	src := filepath.Join(t.TempDir(), "aerospike.conf")
+	if err := os.WriteFile(src, []byte(testImpactConf), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		flags       []string
+		arguments   []string
+		expectError bool
+	}{
+		{name: "impact", arguments: []string{"6.4.0", "7.0.0", src}},
+		{name: "impact with filter", flags: []string{"--filter-path", "namespaces"}, arguments: []string{"7.0.0", "6.4.0", src}},
+		{name: "missing file", arguments: []string{"6.4.0", "7.0.0", "./fake_file.conf"}, expectError: true},
+		{name: "bad format", flags: []string{"--format", "bad"}, arguments: []string{"6.4.0", "7.0.0", src}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newDiffVersionsCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			cmd.ParseFlags(tt.flags)
+			err := cmd.RunE(cmd, tt.arguments)
+			if tt.expectError == (err == nil) {
+				t.Fatalf("expectError: %v does not match err: %v", tt.expectError, err)
+			}
+		})
+	}
+}
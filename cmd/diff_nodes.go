@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	aero "github.com/aerospike/aerospike-client-go/v8"
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/spf13/cobra"
+)
+
+// nodesMatrixPadding is the number of spaces between the columns of the nodes
+// diff matrix.
+const nodesMatrixPadding = 2
+
+// nodesDiffRecord is a node whose config is diffed by diff nodes.
+type nodesDiffRecord struct {
+	Node  string `json:"node"            yaml:"node"`
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// nodesDiffReport is the result of diffing the configs of nodes against each other.
+type nodesDiffReport struct {
+	Nodes       []nodesDiffRecord `json:"nodes"       yaml:"nodes"`
+	Divergences []nodeDivergence  `json:"divergences" yaml:"divergences"`
+}
+
+// failed reports whether the config of any node could not be generated.
+func (r nodesDiffReport) failed() bool {
+	for _, node := range r.Nodes {
+		if node.Error != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func runNodesDiff(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return errDiffNodesTooManyArgs
+	}
+
+	logger.Warning(
+		"This feature is currently in beta. Use at your own risk and please report any issue to support.",
+	)
+
+	outFmt, err := getOutputFormat(cmd, outputFormatText, outputFormatJSON, outputFormatYAML)
+	if err != nil {
+		return err
+	}
+
+	rules, err := getDiffRules(cmd)
+	if err != nil {
+		return err
+	}
+
+	asCommonConfig := aerospikeFlags.NewAerospikeConfig()
+
+	asPolicy, err := asCommonConfig.NewClientPolicy()
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUnableToCreateClientPolicy, err)
+	}
+
+	nodes := hostNodes(asCommonConfig.NewHosts())
+	if len(nodes) < diffNodesHostMin {
+		return errDiffNodesTooFewHosts
+	}
+
+	logger.Debugf("Retrieving Aerospike configuration from %d nodes", len(nodes))
+
+	configs := generateNodeConfigs(nodes, func(host *aero.Host) (*asConf.AsConfig, error) {
+		return generateNodeConfig(host, asPolicy, asConf.AeroConfig)
+	})
+
+	report := diffNodes(configs, rules)
+
+	if err := renderNodesDiff(cmd.OutOrStdout(), outFmt, report); err != nil {
+		return err
+	}
+
+	if report.failed() {
+		return errors.Join(errUnableToDiffAllNodes, ErrSilent)
+	}
+
+	if len(report.Divergences) > 0 {
+		return fmt.Errorf("%w: %w", errDiffConfigsDiffer, ErrSilent)
+	}
+
+	return nil
+}
+
+// hostNodes returns a node for every distinct host, identified by its address.
+func hostNodes(hosts []*aero.Host) []clusterNode {
+	res := make([]clusterNode, 0, len(hosts))
+	seen := map[string]struct{}{}
+
+	for _, host := range hosts {
+		id := host.String()
+		if _, ok := seen[id]; ok {
+			logger.Debugf("Skipping duplicate host %s", id)
+			continue
+		}
+
+		seen[id] = struct{}{}
+		res = append(res, clusterNode{ID: id, Host: host})
+	}
+
+	return res
+}
+
+// diffNodes diffs the node configs against each other after normalizing them
+// with rules.
+func diffNodes(configs []nodeConfig, rules diffRules) nodesDiffReport {
+	report := nodesDiffReport{
+		Nodes:       make([]nodesDiffRecord, 0, len(configs)),
+		Divergences: nodeDivergences(configs, rules),
+	}
+
+	for _, config := range configs {
+		record := nodesDiffRecord{Node: config.node.ID}
+		if config.err != nil {
+			record.Error = config.err.Error()
+		}
+
+		report.Nodes = append(report.Nodes, record)
+	}
+
+	return report
+}
+
+// renderNodesDiff writes the nodes diff report to w in outFmt. The text format is
+// a matrix with a row for every diverging setting and a column for every node.
+func renderNodesDiff(w io.Writer, outFmt string, report nodesDiffReport) error {
+	if outFmt != outputFormatText {
+		return renderStructured(w, outFmt, report)
+	}
+
+	nodes := make([]string, 0, len(report.Nodes))
+
+	for _, node := range report.Nodes {
+		if node.Error != "" {
+			fmt.Fprintf(w, "Unable to generate the config of node %s: %s\n", node.Node, node.Error)
+			continue
+		}
+
+		nodes = append(nodes, node.Node)
+	}
+
+	if len(report.Divergences) == 0 {
+		_, err := fmt.Fprintf(w, "No differences found between %d nodes\n", len(nodes))
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, nodesMatrixPadding, ' ', 0)
+
+	fmt.Fprint(tw, "SETTING")
+
+	for _, node := range nodes {
+		fmt.Fprintf(tw, "\t%s", node)
+	}
+
+	fmt.Fprintln(tw)
+
+	for _, divergence := range report.Divergences {
+		fmt.Fprint(tw, divergence.Path)
+
+		for _, node := range nodes {
+			val := divergence.Values[node]
+			if val == nil {
+				val = "<not set>"
+			}
+
+			fmt.Fprintf(tw, "\t%v", val)
+		}
+
+		fmt.Fprintln(tw)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,110 @@
+//go:build unit
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	aero "github.com/aerospike/aerospike-client-go/v8"
+)
+
+func TestDiffNodes(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	configs := []nodeConfig{
+		testNodeConfig(t, "1", "service {\n\tproto-fd-max 15000\n}\n"),
+		testNodeConfig(t, "2", "service {\n\tproto-fd-max 20000\n\tcluster-name cl1\n}\n"),
+		{node: clusterNode{ID: "3", Host: aero.NewHost("10.0.0.3", 3000)}, err: errUnableToGenerateConfigFromServer},
+	}
+
+	report := diffNodes(configs, diffRules{})
+
+	if len(report.Nodes) != 3 || report.Nodes[2].Error == "" || !report.failed() {
+		t.Fatalf("diffNodes() nodes = %+v, want 3 nodes with node 3 failed", report.Nodes)
+	}
+
+	if len(report.Divergences) != 2 {
+		t.Fatalf("diffNodes() divergences = %+v, want 2", report.Divergences)
+	}
+
+	var buf bytes.Buffer
+	if err := renderNodesDiff(&buf, outputFormatText, report); err != nil {
+		t.Fatalf("renderNodesDiff() error = %v", err)
+	}
+
+	want := "Unable to generate the config of node 3: " + errUnableToGenerateConfigFromServer.Error() + "\n" +
+		"SETTING               1          2\n" +
+		"service.cluster-name  <not set>  cl1\n" +
+		"service.proto-fd-max  15000      20000\n"
+	if buf.String() != want {
+		t.Errorf("renderNodesDiff() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+
+	if err := renderNodesDiff(&buf, outputFormatJSON, report); err != nil {
+		t.Fatalf("renderNodesDiff() error = %v", err)
+	}
+
+	var decoded nodesDiffReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("renderNodesDiff() produced invalid json: %v", err)
+	}
+
+	if len(decoded.Divergences) != 2 || decoded.Divergences[1].Values["2"] != float64(20000) {
+		t.Errorf("renderNodesDiff() json = %s, want the diverging proto-fd-max", buf.String())
+	}
+}
+
+func TestHostNodes(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	nodes := hostNodes([]*aero.Host{
+		aero.NewHost("10.0.0.1", 3000),
+		aero.NewHost("10.0.0.2", 3000),
+		aero.NewHost("10.0.0.1", 3000),
+	})
+
+	if len(nodes) != 2 || nodes[0].ID != "10.0.0.1:3000" || nodes[1].ID != "10.0.0.2:3000" {
+		t.Errorf("hostNodes() = %v, want the 2 distinct hosts", nodes)
+	}
+}
+
+func TestRunENodesDiff(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		flags     []string
+		arguments []string
+		wantErr   error
+	}{
+		{name: "arguments", arguments: []string{"aerospike.conf"}, wantErr: errDiffNodesTooManyArgs},
+		{name: "single host", wantErr: errDiffNodesTooFewHosts},
+		{name: "invalid output format", flags: []string{"--output-format", "unified"}, wantErr: errInvalidOutputFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newDiffNodesCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			cmd.ParseFlags(tt.flags)
+			err := cmd.RunE(cmd, tt.arguments)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunE() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
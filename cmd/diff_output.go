@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/aerospike/asconfig/conf"
+)
+
+// unifiedDiffContext is the number of unchanged lines shown around each change
+// of a unified diff.
+const unifiedDiffContext = 3
+
+// schemaChangeRecord is the machine readable form of a SchemaChange. Path is the
+// config path of the changed setting and Attribute is the part of its schema
+// definition that changed, empty when the setting itself was added or removed.
+type schemaChangeRecord struct {
+	Path      string `json:"path"                yaml:"path"`
+	Attribute string `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+	Old       any    `json:"old"                 yaml:"old"`
+	New       any    `json:"new"                 yaml:"new"`
+}
+
+// sectionChangesRecord is the machine readable form of SectionChanges.
+type sectionChangesRecord struct {
+	Additions     []schemaChangeRecord `json:"additions,omitempty"     yaml:"additions,omitempty"`
+	Removals      []schemaChangeRecord `json:"removals,omitempty"      yaml:"removals,omitempty"`
+	Modifications []schemaChangeRecord `json:"modifications,omitempty" yaml:"modifications,omitempty"`
+}
+
+// changeSummaryRecord is the machine readable form of a ChangeSummary.
+type changeSummaryRecord struct {
+	LowerVersion   string                          `json:"lower-version"   yaml:"lower-version"`
+	UpperVersion   string                          `json:"upper-version"   yaml:"upper-version"`
+	TotalChanges   int                             `json:"total-changes"   yaml:"total-changes"`
+	TotalAdditions int                             `json:"total-additions" yaml:"total-additions"`
+	TotalRemovals  int                             `json:"total-removals"  yaml:"total-removals"`
+	TotalModified  int                             `json:"total-modified"  yaml:"total-modified"`
+	Sections       map[string]sectionChangesRecord `json:"sections"        yaml:"sections"`
+}
+
+// newChangeSummaryRecord converts summary to its machine readable form, keeping
+// only the sections in filterSections when it is not empty.
+func newChangeSummaryRecord(summary ChangeSummary, filterSections map[string]struct{}) changeSummaryRecord {
+	res := changeSummaryRecord{
+		LowerVersion: summary.LowerVersion,
+		UpperVersion: summary.UpperVersion,
+		Sections:     map[string]sectionChangesRecord{},
+	}
+
+	for section, changes := range summary.Sections {
+		if len(filterSections) > 0 {
+			if _, ok := filterSections[section]; !ok {
+				continue
+			}
+		}
+
+		record := sectionChangesRecord{
+			Additions:     newSchemaChangeRecords(changes.Additions),
+			Removals:      newSchemaChangeRecords(changes.Removals),
+			Modifications: newSchemaChangeRecords(changes.Modifications),
+		}
+
+		res.Sections[section] = record
+		res.TotalAdditions += len(record.Additions)
+		res.TotalRemovals += len(record.Removals)
+		res.TotalModified += len(record.Modifications)
+	}
+
+	res.TotalChanges = res.TotalAdditions + res.TotalRemovals + res.TotalModified
+
+	return res
+}
+
+// newSchemaChangeRecords converts changes to their machine readable form.
+func newSchemaChangeRecords(changes []SchemaChange) []schemaChangeRecord {
+	res := make([]schemaChangeRecord, 0, len(changes))
+
+	for _, change := range changes {
+		path, rest := schemaChangeTarget(change.Path)
+		record := schemaChangeRecord{Path: path}
+
+		switch change.Type {
+		case Addition:
+			record.New = change.Value
+		case Removal:
+			record.Old = change.OldValue
+		case Modification:
+			record.Old = change.OldValue
+			record.New = change.Value
+		}
+
+		// modified array items are reported as changes to the whole array
+		if (change.OldFullValue != nil || change.NewFullValue != nil) && len(rest) > 0 {
+			rest = rest[:len(rest)-1]
+			record.Old = change.OldFullValue
+			record.New = change.NewFullValue
+		}
+
+		record.Attribute = formatPath(strings.Join(rest, "/"))
+		res = append(res, record)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Path != res[j].Path {
+			return res[i].Path < res[j].Path
+		}
+
+		return res[i].Attribute < res[j].Attribute
+	})
+
+	return res
+}
+
+// renderChangeSummaryRecord writes the change summary to w in the json, yaml or
+// markdown output format.
+func renderChangeSummaryRecord(w io.Writer, outFmt string, record changeSummaryRecord) error {
+	switch outFmt {
+	case outputFormatMarkdown:
+		return renderChangeSummaryMarkdown(w, record)
+	default:
+		return renderStructured(w, outFmt, record)
+	}
+}
+
+// renderChangeSummaryMarkdown writes the change summary to w as a markdown
+// document with a table of changes for each section.
+func renderChangeSummaryMarkdown(w io.Writer, record changeSummaryRecord) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Configuration changes from %s to %s\n\n", record.LowerVersion, record.UpperVersion)
+	fmt.Fprintf(&sb, "%d changes: %d additions, %d removals, %d modifications\n",
+		record.TotalChanges, record.TotalAdditions, record.TotalRemovals, record.TotalModified)
+
+	sections := make([]string, 0, len(record.Sections))
+	for section := range record.Sections {
+		sections = append(sections, section)
+	}
+
+	sort.Strings(sections)
+
+	for _, section := range sections {
+		changes := record.Sections[section]
+
+		fmt.Fprintf(&sb, "\n## %s\n\n", section)
+		sb.WriteString("| Change | Path | Attribute | Old | New |\n")
+		sb.WriteString("| --- | --- | --- | --- | --- |\n")
+
+		groups := []struct {
+			name    string
+			changes []schemaChangeRecord
+		}{
+			{name: "removed", changes: changes.Removals},
+			{name: "added", changes: changes.Additions},
+			{name: "modified", changes: changes.Modifications},
+		}
+
+		for _, group := range groups {
+			for _, change := range group.changes {
+				fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", group.name,
+					markdownCell(change.Path), markdownCell(change.Attribute),
+					markdownValue(change.Old), markdownValue(change.New))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// markdownValue summarizes a schema value for a markdown table cell.
+func markdownValue(val any) string {
+	if val == nil {
+		return ""
+	}
+
+	return markdownCell(unwrapParentheses(getValueSummary(val)))
+}
+
+// markdownCell escapes text for use in a markdown table cell.
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(text)
+}
+
+// Kinds of difference between a left and right config.
+const (
+	diffKindAdded   = "added"
+	diffKindRemoved = "removed"
+	diffKindChanged = "changed"
+)
+
+// configDiffRecord is a difference between a left and right config. Path is the
+// name resolved config context of the setting, Left and Right are its values,
+// nil on the side where it is not set. Implicit is the side whose value is a
+// schema default filled in by an effective diff.
+type configDiffRecord struct {
+	Path     string `json:"path"               yaml:"path"`
+	Kind     string `json:"kind"               yaml:"kind"`
+	Left     any    `json:"left"               yaml:"left"`
+	Right    any    `json:"right"              yaml:"right"`
+	Implicit string `json:"implicit,omitempty" yaml:"implicit,omitempty"`
+
+	// key is the flat map key of the setting
+	key string
+}
+
+// configDiffReport is the machine readable result of diffing two configs.
+type configDiffReport struct {
+	Left        string             `json:"left"        yaml:"left"`
+	Right       string             `json:"right"       yaml:"right"`
+	Differences []configDiffRecord `json:"differences" yaml:"differences"`
+}
+
+// renderConfigDiff writes the differences between the left and right configs to
+// w in the json, yaml or unified output format. The unified format compares the
+// configs marshalled to text in format.
+func renderConfigDiff(
+	w io.Writer,
+	outFmt string,
+	report configDiffReport,
+	left, right *asConf.AsConfig,
+	format asConf.Format,
+) error {
+	if outFmt != outputFormatUnified {
+		return renderStructured(w, outFmt, report)
+	}
+
+	leftText, err := conf.NewConfigMarshaller(left, format).MarshalText()
+	if err != nil {
+		return err
+	}
+
+	rightText, err := conf.NewConfigMarshaller(right, format).MarshalText()
+	if err != nil {
+		return err
+	}
+
+	text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(leftText)),
+		B:        difflib.SplitLines(string(rightText)),
+		FromFile: report.Left,
+		ToFile:   report.Right,
+		Context:  unifiedDiffContext,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, text)
+
+	return err
+}
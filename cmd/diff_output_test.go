@@ -0,0 +1,284 @@
+//go:build unit
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"gopkg.in/yaml.v3"
+)
+
+func testChangeSummary(t *testing.T) ChangeSummary {
+	t.Helper()
+
+	var schemaLower, schemaUpper map[string]any
+	if err := json.Unmarshal([]byte(testImpactSchemaLower), &schemaLower); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	if err := json.Unmarshal([]byte(testImpactSchemaUpper), &schemaUpper); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	summary, err := compareSchemas(schemaLower, schemaUpper, "6.4.0", "7.0.0")
+	if err != nil {
+		t.Fatalf("compareSchemas() error = %v", err)
+	}
+
+	return summary
+}
+
+func TestNewChangeSummaryRecord(t *testing.T) {
+	record := newChangeSummaryRecord(testChangeSummary(t), nil)
+
+	if record.LowerVersion != "6.4.0" || record.UpperVersion != "7.0.0" {
+		t.Errorf("newChangeSummaryRecord() versions = %s, %s, want 6.4.0, 7.0.0", record.LowerVersion, record.UpperVersion)
+	}
+
+	service := record.Sections["service"]
+
+	if len(service.Removals) != 1 || service.Removals[0].Path != "service.old-setting" ||
+		service.Removals[0].Attribute != "" {
+		t.Errorf("newChangeSummaryRecord() service removals = %+v, want service.old-setting", service.Removals)
+	}
+
+	want := []schemaChangeRecord{
+		{Path: "service.debug-allocations", Attribute: "default", Old: false, New: "none"},
+		{Path: "service.debug-allocations", Attribute: "type", Old: "boolean", New: "string"},
+	}
+
+	if len(service.Modifications) != len(want) {
+		t.Fatalf("newChangeSummaryRecord() service modifications = %+v, want %+v", service.Modifications, want)
+	}
+
+	for i, w := range want {
+		if service.Modifications[i] != w {
+			t.Errorf("newChangeSummaryRecord() service modification %d = %+v, want %+v", i, service.Modifications[i], w)
+		}
+	}
+
+	filtered := newChangeSummaryRecord(testChangeSummary(t), map[string]struct{}{"service": {}})
+	if _, ok := filtered.Sections["namespaces"]; ok || filtered.TotalChanges != 3 {
+		t.Errorf("newChangeSummaryRecord() filtered = %+v, want only the 3 service changes", filtered)
+	}
+}
+
+func TestRenderChangeSummaryRecord(t *testing.T) {
+	record := newChangeSummaryRecord(testChangeSummary(t), nil)
+
+	var buf bytes.Buffer
+	if err := renderChangeSummaryRecord(&buf, outputFormatJSON, record); err != nil {
+		t.Fatalf("renderChangeSummaryRecord() error = %v", err)
+	}
+
+	var decoded changeSummaryRecord
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("renderChangeSummaryRecord() produced invalid json: %v", err)
+	}
+
+	if decoded.TotalChanges != record.TotalChanges {
+		t.Errorf("renderChangeSummaryRecord() json total-changes = %d, want %d", decoded.TotalChanges, record.TotalChanges)
+	}
+
+	buf.Reset()
+
+	if err := renderChangeSummaryRecord(&buf, outputFormatYAML, record); err != nil {
+		t.Fatalf("renderChangeSummaryRecord() error = %v", err)
+	}
+
+	decoded = changeSummaryRecord{}
+	if err := yaml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("renderChangeSummaryRecord() produced invalid yaml: %v", err)
+	}
+
+	if len(decoded.Sections) != len(record.Sections) {
+		t.Errorf("renderChangeSummaryRecord() yaml sections = %v, want %d", decoded.Sections, len(record.Sections))
+	}
+
+	buf.Reset()
+
+	if err := renderChangeSummaryRecord(&buf, outputFormatMarkdown, record); err != nil {
+		t.Fatalf("renderChangeSummaryRecord() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"# Configuration changes from 6.4.0 to 7.0.0\n",
+		"\n## service\n",
+		"| removed | service.old-setting |  | integer, default: 0 |  |\n",
+		"| modified | service.debug-allocations | type | boolean | string |\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("renderChangeSummaryRecord() markdown = %s, want it to contain %q", buf.String(), want)
+		}
+	}
+}
+
+func TestRunEVersionsDiffOutputFormat(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		flags       []string
+		expectError bool
+	}{
+		{name: "json", flags: []string{"--output-format", "json"}},
+		{name: "yaml with filter", flags: []string{"--output-format", "yaml", "--filter-path", "service"}},
+		{name: "markdown", flags: []string{"--output-format", "markdown"}},
+		{name: "invalid", flags: []string{"--output-format", "sarif"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newDiffVersionsCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			cmd.ParseFlags(tt.flags)
+			err := cmd.RunE(cmd, []string{"7.0.0", "8.0.0"})
+			if tt.expectError == (err == nil) {
+				t.Fatalf("expectError: %v does not match err: %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestDiffFlatMapRecords(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	left := map[string]any{
+		"service.proto-fd-max":                 15000,
+		"namespaces.{test}.replication-factor": 2,
+		"namespaces.{test}.<index>":            0,
+		"namespaces.{test}.memory-size":        int64(4 << 30),
+		"logging.{/var/log/aerospike.log}.any": "info",
+	}
+	right := map[string]any{
+		"service.proto-fd-max":                 20000,
+		"namespaces.{test}.replication-factor": 2,
+		"namespaces.{test}.<index>":            1,
+		"namespaces.{test}.evict-used-pct":     70,
+		"logging.{/var/log/aerospike.log}.any": "INFO",
+	}
+
+	want := []configDiffRecord{
+		{Path: "namespaces.test.evict-used-pct", Kind: diffKindAdded, Right: 70},
+		{Path: "namespaces.test.memory-size", Kind: diffKindRemoved, Left: int64(4 << 30)},
+		{Path: "service.proto-fd-max", Kind: diffKindChanged, Left: 15000, Right: 20000},
+	}
+
+	got := diffFlatMapRecords(left, right)
+	if len(got) != len(want) {
+		t.Fatalf("diffFlatMapRecords() = %+v, want %+v", got, want)
+	}
+
+	for i, w := range want {
+		if got[i].Path != w.Path || got[i].Kind != w.Kind || got[i].Left != w.Left || got[i].Right != w.Right {
+			t.Errorf("diffFlatMapRecords()[%d] = %+v, want %+v", i, got[i], w)
+		}
+	}
+}
+
+func TestRenderConfigDiff(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	left, err := asConf.NewASConfigFromBytes(mgmtLibLogger, []byte("service {\n\tproto-fd-max 15000\n}\n"), asConf.AeroConfig)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	right, err := asConf.NewASConfigFromBytes(mgmtLibLogger, []byte("service {\n\tproto-fd-max 20000\n}\n"), asConf.AeroConfig)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	report := configDiffReport{
+		Left:        "left.conf",
+		Right:       "right.conf",
+		Differences: diffFlatMapRecords(*left.GetFlatMap(), *right.GetFlatMap()),
+	}
+
+	var buf bytes.Buffer
+	if err := renderConfigDiff(&buf, outputFormatJSON, report, left, right, asConf.AeroConfig); err != nil {
+		t.Fatalf("renderConfigDiff() error = %v", err)
+	}
+
+	var decoded configDiffReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("renderConfigDiff() produced invalid json: %v", err)
+	}
+
+	if len(decoded.Differences) != 1 || decoded.Differences[0].Path != "service.proto-fd-max" ||
+		decoded.Differences[0].Kind != diffKindChanged {
+		t.Errorf("renderConfigDiff() json = %s, want the changed proto-fd-max", buf.String())
+	}
+
+	buf.Reset()
+
+	if err := renderConfigDiff(&buf, outputFormatUnified, report, left, right, asConf.AeroConfig); err != nil {
+		t.Fatalf("renderConfigDiff() error = %v", err)
+	}
+
+	for _, want := range []string{"--- left.conf\n", "+++ right.conf\n", "-    proto-fd-max    15000\n", "+    proto-fd-max    20000\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("renderConfigDiff() unified = %q, want it to contain %q", buf.String(), want)
+		}
+	}
+}
+
+func TestRunEFileDiffOutputFormat(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	dir := t.TempDir()
+	path1 := filepath.Join(dir, "aerospike1.conf")
+	path2 := filepath.Join(dir, "aerospike2.conf")
+
+	if err := os.WriteFile(path1, []byte("service {\n\tproto-fd-max 15000\n}\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if err := os.WriteFile(path2, []byte("service {\n\tproto-fd-max 20000\n}\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		flags     []string
+		arguments []string
+		wantErr   error
+	}{
+		{name: "json differ", flags: []string{"--output-format", "json"}, arguments: []string{path1, path2}, wantErr: errDiffConfigsDiffer},
+		{name: "yaml same", flags: []string{"--output-format", "yaml"}, arguments: []string{path1, path1}},
+		{name: "unified differ", flags: []string{"--output-format", "unified"}, arguments: []string{path1, path2}, wantErr: errDiffConfigsDiffer},
+		{name: "invalid", flags: []string{"--output-format", "markdown"}, arguments: []string{path1, path2}, wantErr: errInvalidOutputFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newDiffFilesCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			cmd.ParseFlags(tt.flags)
+			err := cmd.RunE(cmd, tt.arguments)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunE() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,390 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	errInvalidDiffRules       = errors.New("invalid diff rules")
+	errInvalidDiffGlob        = errors.New("invalid path glob")
+	errDiffRulesUnifiedFormat = errors.New("--ignore, --rules and --effective can not be used with the unified output format")
+)
+
+// timeSuffixes are the multipliers of the time units accepted by parseTime.
+var timeSuffixes = map[byte]uint64{
+	'S': 1,
+	'M': 60,
+	'H': 60 * 60,
+	'D': 24 * 60 * 60,
+}
+
+// diffRulesFile is the normalization rules file of the config diffs, written in
+// yaml. Paths are globs of name resolved config paths where "*" matches any
+// sequence of characters and "?" any single character.
+//
+//	ignore:
+//	  - service.node-id
+//	  - network.service.access-address
+//	units:
+//	  size:
+//	    - namespaces.*.storage-engine.filesize
+//	  time:
+//	    - namespaces.*.default-ttl
+//	case-insensitive:
+//	  - namespaces.*.storage-engine.type
+//	defaults:
+//	  namespaces.*.replication-factor: 2
+type diffRulesFile struct {
+	Ignore          []string       `yaml:"ignore"`
+	Units           diffUnitsFile  `yaml:"units"`
+	CaseInsensitive []string       `yaml:"case-insensitive"`
+	Defaults        map[string]any `yaml:"defaults"`
+}
+
+// diffUnitsFile lists the paths whose values are sizes, ex: 4G, or times, ex: 1h.
+type diffUnitsFile struct {
+	Size []string `yaml:"size"`
+	Time []string `yaml:"time"`
+}
+
+// diffDefault is the default value of the settings matching path.
+type diffDefault struct {
+	path  *regexp.Regexp
+	value any
+}
+
+// diffRules normalize flattened config maps before they are diffed so that only
+// meaningful differences are reported. The zero value changes nothing.
+type diffRules struct {
+	ignore          []*regexp.Regexp
+	sizes           []*regexp.Regexp
+	times           []*regexp.Regexp
+	caseInsensitive []*regexp.Regexp
+	defaults        []diffDefault
+}
+
+// empty reports whether the rules leave configs unchanged.
+func (r diffRules) empty() bool {
+	return len(r.ignore) == 0 && len(r.sizes) == 0 && len(r.times) == 0 &&
+		len(r.caseInsensitive) == 0 && len(r.defaults) == 0
+}
+
+// getDiffRules returns the rules of the --rules file, if one is provided, and
+// the globs of the --ignore flag.
+func getDiffRules(cmd *cobra.Command) (diffRules, error) {
+	var rulesFile diffRulesFile
+
+	rulesPath, err := cmd.Flags().GetString("rules")
+	if err != nil {
+		return diffRules{}, err
+	}
+
+	logger.Debugf("Processing flag rules value=%s", rulesPath)
+
+	if rulesPath != "" {
+		data, err := os.ReadFile(rulesPath)
+		if err != nil {
+			return diffRules{}, err
+		}
+
+		rulesFile, err = loadDiffRulesFile(data)
+		if err != nil {
+			return diffRules{}, fmt.Errorf("%s: %w", rulesPath, err)
+		}
+	}
+
+	ignore, err := cmd.Flags().GetStringSlice("ignore")
+	if err != nil {
+		return diffRules{}, err
+	}
+
+	logger.Debugf("Processing flag ignore value=%v", ignore)
+
+	rulesFile.Ignore = append(rulesFile.Ignore, ignore...)
+
+	return newDiffRules(rulesFile)
+}
+
+// loadDiffRulesFile parses a yaml diff rules file.
+func loadDiffRulesFile(data []byte) (diffRulesFile, error) {
+	var res diffRulesFile
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	// an empty rules file has no rules
+	if err := dec.Decode(&res); err != nil && !errors.Is(err, io.EOF) {
+		return diffRulesFile{}, fmt.Errorf("%w: %w", errInvalidDiffRules, err)
+	}
+
+	return res, nil
+}
+
+// newDiffRules compiles the globs of rulesFile.
+func newDiffRules(rulesFile diffRulesFile) (diffRules, error) {
+	var (
+		res diffRules
+		err error
+	)
+
+	if res.ignore, err = compileDiffGlobs(rulesFile.Ignore); err != nil {
+		return diffRules{}, err
+	}
+
+	if res.sizes, err = compileDiffGlobs(rulesFile.Units.Size); err != nil {
+		return diffRules{}, err
+	}
+
+	if res.times, err = compileDiffGlobs(rulesFile.Units.Time); err != nil {
+		return diffRules{}, err
+	}
+
+	if res.caseInsensitive, err = compileDiffGlobs(rulesFile.CaseInsensitive); err != nil {
+		return diffRules{}, err
+	}
+
+	for glob, value := range rulesFile.Defaults {
+		path, err := compileDiffGlob(glob)
+		if err != nil {
+			return diffRules{}, err
+		}
+
+		res.defaults = append(res.defaults, diffDefault{path: path, value: value})
+	}
+
+	return res, nil
+}
+
+func compileDiffGlobs(globs []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(globs))
+
+	for _, glob := range globs {
+		re, err := compileDiffGlob(glob)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, re)
+	}
+
+	return res, nil
+}
+
+// compileDiffGlob converts a path glob to a regular expression matching the
+// whole path.
+func compileDiffGlob(glob string) (*regexp.Regexp, error) {
+	glob = strings.TrimSpace(glob)
+	if glob == "" {
+		return nil, fmt.Errorf("%w: empty glob", errInvalidDiffGlob)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("^")
+
+	for _, r := range glob {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errInvalidDiffGlob, glob, err)
+	}
+
+	return re, nil
+}
+
+// matchesAny reports whether path matches one of globs.
+func matchesAny(globs []*regexp.Regexp, path string) bool {
+	for _, glob := range globs {
+		if glob.MatchString(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalize returns a copy of the flattened config map without the ignored
+// settings and settings set to their default, and with sizes, times and case
+// insensitive values in a canonical form.
+func (r diffRules) normalize(flatMap map[string]any) map[string]any {
+	if r.empty() {
+		return flatMap
+	}
+
+	res := make(map[string]any, len(flatMap))
+
+	for key, val := range flatMap {
+		path, _ := flatKeyContext(key)
+
+		if matchesAny(r.ignore, path) {
+			logger.Debugf("Ignoring %s", path)
+			continue
+		}
+
+		val = canonicalNumbers(r.normalizeValue(path, val))
+
+		if r.isDefault(path, val) {
+			logger.Debugf("Ignoring %s set to its default value", path)
+			continue
+		}
+
+		res[key] = val
+	}
+
+	return res
+}
+
+// normalizeValue returns val, the value of the setting at path, in canonical form.
+func (r diffRules) normalizeValue(path string, val any) any {
+	switch {
+	case matchesAny(r.sizes, path):
+		return mapStrings(val, func(s string) any {
+			if n, err := parseSize(s); err == nil {
+				return n
+			}
+
+			return s
+		})
+	case matchesAny(r.times, path):
+		return mapStrings(val, func(s string) any {
+			if n, err := parseTime(s); err == nil {
+				return n
+			}
+
+			return s
+		})
+	case matchesAny(r.caseInsensitive, path):
+		return mapStrings(val, func(s string) any { return strings.ToLower(s) })
+	default:
+		return val
+	}
+}
+
+// isDefault reports whether val is the default value of the setting at path.
+// Values are compared in their canonical form.
+func (r diffRules) isDefault(path string, val any) bool {
+	for _, def := range r.defaults {
+		if def.path.MatchString(path) &&
+			diffValueString(r.normalizeValue(path, def.value)) == diffValueString(val) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mapStrings applies fn to val if it is a string, or to its string items if it
+// is a list. Other values are returned unchanged.
+func mapStrings(val any, fn func(string) any) any {
+	switch v := val.(type) {
+	case string:
+		return fn(v)
+	case []string:
+		res := make([]any, len(v))
+		for i, s := range v {
+			res[i] = fn(s)
+		}
+
+		return res
+	case []any:
+		res := make([]any, len(v))
+		for i, item := range v {
+			res[i] = mapStrings(item, fn)
+		}
+
+		return res
+	default:
+		return val
+	}
+}
+
+// canonicalNumbers returns val with its numbers, and the numbers of its list
+// items, as int64 when they are whole and as float64 otherwise. Unsigned numbers
+// too large for an int64 are kept. Sizes, times and the numbers of conf, yaml
+// and server configs have different types and only compare equal in this form.
+func canonicalNumbers(val any) any {
+	if list, ok := val.([]any); ok {
+		res := make([]any, len(list))
+		for i, item := range list {
+			res[i] = canonicalNumbers(item)
+		}
+
+		return res
+	}
+
+	num := reflect.ValueOf(val)
+
+	switch num.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return num.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n := num.Uint(); n <= math.MaxInt64 {
+			return int64(n)
+		}
+
+		return val
+	case reflect.Float32, reflect.Float64:
+		if f := num.Float(); f == math.Trunc(f) && math.Abs(f) < math.MaxInt64 {
+			return int64(f)
+		}
+
+		return num.Float()
+	default:
+		return val
+	}
+}
+
+// diffValueString formats a value for comparison, whole floats are formatted
+// without a fraction so that yaml and conf numbers compare equal.
+func diffValueString(v any) string {
+	if num, ok := v.(float64); ok {
+		return strconv.FormatFloat(num, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(v)
+}
+
+// parseTime parses a time in seconds with an optional S, M, H or D suffix.
+// Ex: 30D.
+func parseTime(val string) (uint64, error) {
+	if val == "" {
+		return 0, nil
+	}
+
+	multiplier := uint64(1)
+
+	if m, ok := timeSuffixes[strings.ToUpper(val)[len(val)-1]]; ok {
+		multiplier = m
+		val = val[:len(val)-1]
+	}
+
+	n, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return n * multiplier, nil
+}
@@ -0,0 +1,271 @@
+//go:build unit
+
+package cmd
+
+import (
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testDiffRules = `
+ignore:
+  - service.node-id
+units:
+  size:
+    - namespaces.*.storage-engine.filesize
+  time:
+    - namespaces.*.default-ttl
+case-insensitive:
+  - namespaces.*.storage-engine.type
+defaults:
+  namespaces.*.replication-factor: 2
+`
+
+func TestDiffRulesNormalize(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	rulesFile, err := loadDiffRulesFile([]byte(testDiffRules))
+	if err != nil {
+		t.Fatalf("loadDiffRulesFile() error = %v", err)
+	}
+
+	rulesFile.Ignore = append(rulesFile.Ignore, "network.service.access-*")
+
+	rules, err := newDiffRules(rulesFile)
+	if err != nil {
+		t.Fatalf("newDiffRules() error = %v", err)
+	}
+
+	// numbers are uint64 in conf configs, int in yaml configs and int64 in server configs
+	got := rules.normalize(map[string]any{
+		"service.node-id":                               "A1",
+		"service.proto-fd-max":                          uint64(15000),
+		"network.service.access-address":                []string{"10.0.0.1"},
+		"network.service.access-port":                   3000,
+		"namespaces.{test}.<index>":                     0,
+		"namespaces.{test}.replication-factor":          uint64(2),
+		"namespaces.{test}.default-ttl":                 "30D",
+		"namespaces.{test}.storage-engine.type":         "DEVICE",
+		"namespaces.{test}.storage-engine.filesize":     "4G",
+		"namespaces.{bar}.replication-factor":           3,
+		"namespaces.{bar}.storage-engine.filesize":      int64(1024),
+		"namespaces.{bar}.storage-engine.not-filesize":  "4G",
+		"namespaces.{bar}.storage-engine.type":          "memory",
+		"namespaces.{bar}.storage-engine.unknown-units": "4X",
+	})
+
+	want := map[string]any{
+		"service.proto-fd-max":                          int64(15000),
+		"namespaces.{test}.<index>":                     int64(0),
+		"namespaces.{test}.default-ttl":                 int64(30 * 24 * 60 * 60),
+		"namespaces.{test}.storage-engine.type":         "device",
+		"namespaces.{test}.storage-engine.filesize":     int64(4 << 30),
+		"namespaces.{bar}.replication-factor":           int64(3),
+		"namespaces.{bar}.storage-engine.filesize":      int64(1024),
+		"namespaces.{bar}.storage-engine.not-filesize":  "4G",
+		"namespaces.{bar}.storage-engine.type":          "memory",
+		"namespaces.{bar}.storage-engine.unknown-units": "4X",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalize() = %v, want %v", got, want)
+	}
+
+	flatMap := map[string]any{"service.proto-fd-max": uint64(15000)}
+	if got := (diffRules{}).normalize(flatMap); !reflect.DeepEqual(got, flatMap) {
+		t.Errorf("normalize() with no rules = %v, want %v", got, flatMap)
+	}
+}
+
+func TestDiffConfigFlatMapsRules(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	rulesFile, err := loadDiffRulesFile([]byte(testDiffRules))
+	if err != nil {
+		t.Fatalf("loadDiffRulesFile() error = %v", err)
+	}
+
+	rules, err := newDiffRules(rulesFile)
+	if err != nil {
+		t.Fatalf("newDiffRules() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		local  map[string]any
+		server map[string]any
+		want   int
+	}{
+		{
+			name:   "units against plain numbers",
+			local:  map[string]any{"namespaces.{test}.default-ttl": "30D", "namespaces.{test}.storage-engine.filesize": "4G"},
+			server: map[string]any{"namespaces.{test}.default-ttl": int64(2592000), "namespaces.{test}.storage-engine.filesize": int64(4 << 30)},
+		},
+		{
+			name:   "conf and server numbers",
+			local:  map[string]any{"service.proto-fd-max": uint64(15000), "namespaces.{test}.default-ttl": uint64(0)},
+			server: map[string]any{"service.proto-fd-max": float64(15000), "namespaces.{test}.default-ttl": "0"},
+		},
+		{
+			name:   "different sizes",
+			local:  map[string]any{"namespaces.{test}.storage-engine.filesize": "4G"},
+			server: map[string]any{"namespaces.{test}.storage-engine.filesize": int64(2 << 30)},
+			want:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffConfigFlatMaps(tt.local, tt.server, rules, nil); len(got) != tt.want {
+				t.Errorf("diffConfigFlatMaps() = %+v, want %d differences", got, tt.want)
+			}
+		})
+	}
+
+	// numbers are only normalized with rules
+	local := map[string]any{"service.proto-fd-max": uint64(15000)}
+	server := map[string]any{"service.proto-fd-max": float64(15000)}
+
+	if got := diffConfigFlatMaps(local, server, diffRules{}, nil); len(got) != 1 {
+		t.Errorf("diffConfigFlatMaps() without rules = %+v, want 1 difference", got)
+	}
+}
+
+func TestCanonicalNumbers(t *testing.T) {
+	tests := []struct {
+		val  any
+		want any
+	}{
+		{val: uint64(15000), want: int64(15000)},
+		{val: 2, want: int64(2)},
+		{val: float64(-1), want: int64(-1)},
+		{val: float64(1.5), want: float64(1.5)},
+		{val: uint64(math.MaxUint64), want: uint64(math.MaxUint64)},
+		{val: "none", want: "none"},
+		{val: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := canonicalNumbers(tt.val); got != tt.want {
+			t.Errorf("canonicalNumbers(%v) = %v (%T), want %v (%T)", tt.val, got, got, tt.want, tt.want)
+		}
+	}
+
+	if got := canonicalNumbers([]any{uint64(1), "a"}); !reflect.DeepEqual(got, []any{int64(1), "a"}) {
+		t.Errorf("canonicalNumbers() of a list = %v, want its numbers as int64", got)
+	}
+}
+
+func TestCompileDiffGlob(t *testing.T) {
+	tests := []struct {
+		glob    string
+		path    string
+		want    bool
+		wantErr bool
+	}{
+		{glob: "service.node-id", path: "service.node-id", want: true},
+		{glob: "service.node-id", path: "service.node-id-interface"},
+		{glob: "namespaces.*.rack-id", path: "namespaces.test.rack-id", want: true},
+		{glob: "logging.*", path: "logging./var/log/aerospike.log.any", want: true},
+		{glob: "namespaces.tes?.rack-id", path: "namespaces.test.rack-id", want: true},
+		{glob: "namespaces.(test).rack-id", path: "namespaces.test.rack-id"},
+		{glob: " ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		re, err := compileDiffGlob(tt.glob)
+		if tt.wantErr {
+			if !errors.Is(err, errInvalidDiffGlob) {
+				t.Errorf("compileDiffGlob(%q) error = %v, want %v", tt.glob, err, errInvalidDiffGlob)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("compileDiffGlob(%q) error = %v", tt.glob, err)
+		}
+
+		if got := re.MatchString(tt.path); got != tt.want {
+			t.Errorf("compileDiffGlob(%q) matches %s = %v, want %v", tt.glob, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoadDiffRulesFile(t *testing.T) {
+	if _, err := loadDiffRulesFile([]byte("")); err != nil {
+		t.Errorf("loadDiffRulesFile() of an empty file error = %v", err)
+	}
+
+	if _, err := loadDiffRulesFile([]byte("ignores:\n  - service.node-id\n")); !errors.Is(err, errInvalidDiffRules) {
+		t.Errorf("loadDiffRulesFile() of an unknown field error = %v, want %v", err, errInvalidDiffRules)
+	}
+}
+
+func TestRunEFileDiffRules(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	dir := t.TempDir()
+	path1 := filepath.Join(dir, "aerospike1.conf")
+	path2 := filepath.Join(dir, "aerospike2.conf")
+	rulesPath := filepath.Join(dir, "rules.yaml")
+
+	conf1 := "service {\n\tnode-id A1\n}\nnamespace test {\n\tdefault-ttl 30D\n}\n"
+	conf2 := "service {\n\tnode-id A2\n}\nnamespace test {\n\tdefault-ttl 2592000\n\treplication-factor 2\n}\n"
+	yaml1 := "namespaces:\n  - name: test\n    default-ttl: 30D\n    storage-engine:\n      type: DEVICE\n      filesize: 4G\n"
+	yaml2 := "namespaces:\n  - name: test\n    default-ttl: 2592000\n    storage-engine:\n      type: device\n      filesize: 4294967296\n"
+	yamlPath1 := filepath.Join(dir, "aerospike1.yaml")
+	yamlPath2 := filepath.Join(dir, "aerospike2.yaml")
+
+	files := map[string]string{path1: conf1, path2: conf2, yamlPath1: yaml1, yamlPath2: yaml2, rulesPath: testDiffRules}
+	for path, data := range files {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		flags   []string
+		args    []string
+		wantErr error
+	}{
+		{name: "no rules", flags: []string{"--output-format", "json"}, wantErr: errDiffConfigsDiffer},
+		{name: "ignore only", flags: []string{"--output-format", "json", "--ignore", "service.node-id"}, wantErr: errDiffConfigsDiffer},
+		{name: "rules", flags: []string{"--output-format", "json", "--rules", rulesPath}},
+		{name: "yaml no rules", flags: []string{"--output-format", "json"}, args: []string{yamlPath1, yamlPath2}, wantErr: errDiffConfigsDiffer},
+		{name: "yaml rules", flags: []string{"--output-format", "json", "--rules", rulesPath}, args: []string{yamlPath1, yamlPath2}},
+		{name: "unified", flags: []string{"--output-format", "unified", "--ignore", "service.*"}, wantErr: errDiffRulesUnifiedFormat},
+		{name: "missing rules file", flags: []string{"--rules", filepath.Join(dir, "missing.yaml")}, wantErr: os.ErrNotExist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newDiffFilesCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			args := tt.args
+			if args == nil {
+				args = []string{path1, path2}
+			}
+
+			cmd.ParseFlags(tt.flags)
+			err := cmd.RunE(cmd, args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunE() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -811,6 +811,7 @@ func TestVersionsDiffCLIIntegration(t *testing.T) {
 				"versions",
 				firstVersion,
 				secondVersion,
+				"aerospike.conf",
 				availableVersions[len(availableVersions)-1],
 			},
 			expectError: true,
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
 	"github.com/wI2L/jsondiff"
 )
 
@@ -1070,6 +1071,28 @@ func formatPath(path string) string {
 	return strings.Join(result, ".")
 }
 
+// flatKeyIndex is the flat map key that records the position of a list item.
+const flatKeyIndex = "<index>"
+
+// flatKeyContext returns the name resolved config context of a key of the
+// management lib's flat config map, and the config path of the key without
+// list item names.
+func flatKeyContext(key string) (context, path string) {
+	var contextParts, pathParts []string
+
+	for _, segment := range asConf.SplitKey(mgmtLibLogger, key, ".") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			contextParts = append(contextParts, strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}"))
+			continue
+		}
+
+		pathParts = append(pathParts, segment)
+		contextParts = append(contextParts, segment)
+	}
+
+	return strings.Join(contextParts, "."), strings.Join(pathParts, ".")
+}
+
 // isNumeric checks if a string is numeric.
 func isNumeric(s string) bool {
 	_, err := strconv.Atoi(s)
@@ -0,0 +1,503 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aerospike/asconfig/conf"
+	"github.com/aerospike/asconfig/conf/metadata"
+)
+
+const (
+	editArgMin = 2
+	getArgs    = 2
+)
+
+var (
+	errEditTooFewArgs        = fmt.Errorf("expected at least %d arguments: <path/to/config_file> <context>", editArgMin)
+	errGetArgs               = fmt.Errorf("get expects %d arguments: <path/to/config_file> <context>", getArgs)
+	errInvalidEditAssignment = errors.New("invalid assignment, expected <context>=<value>")
+	errEditContextNotFound   = errors.New("context not found")
+	errEditContextNotSection = errors.New("context is not a section")
+	errEditNamedListItem     = errors.New("named list items can only be unset, set their settings instead")
+	errEditValidationFailed  = errors.New("the edited configuration is not valid")
+	editOutputFormats        = []string{outputFormatText, outputFormatJSON}
+)
+
+// getEditFlags returns the flags of the commands that write back an edited config.
+func getEditFlags() *pflag.FlagSet {
+	res := getCommonFlags()
+	res.BoolP("force", "f", false, "Write the edited configuration even if it fails validation.")
+	res.StringP("format", "F", "conf",
+		"The format of the source file. Valid options are: yaml, yml, json, toml, hcl, and conf.")
+
+	return res
+}
+
+func newSetCmd() *cobra.Command {
+	res := &cobra.Command{
+		Use:   "set [flags] <path/to/config_file> <context=value>...",
+		Short: "Set configuration values in place.",
+		Long: `Set changes the settings of a configuration file in place.
+				Settings are addressed by their dotted context, where named list items such
+				as namespaces, sets and logging sinks are addressed by name, the same
+				contexts asconfig uses in validation errors. Ex: namespaces.test.replication-factor.
+				Values are parsed as yaml, so numbers and booleans keep their type and lists
+				can be written as [a, b]. Missing sections are created, and a named list item
+				that does not exist is added to its list.
+				The edited configuration is validated for the --aerospike-version, or the
+				version in the file metadata, unless --force is used, and is written back
+				in its format. Comments and ordering are kept in yaml and conf files.`,
+		Example: `
+				# Raise the replication factor of the test namespace
+				asconfig set aerospike.conf namespaces.test.replication-factor=3
+				# Set several values of a yaml config
+				asconfig set aerospike.yaml service.proto-fd-max=100000 namespaces.bar.storage-engine.type=memory`,
+		RunE: runSetCommand,
+	}
+
+	res.Flags().AddFlagSet(getEditFlags())
+
+	res.Version = VERSION
+
+	return res
+}
+
+func newUnsetCmd() *cobra.Command {
+	res := &cobra.Command{
+		Use:   "unset [flags] <path/to/config_file> <context>...",
+		Short: "Remove configuration values in place.",
+		Long: `Unset removes settings, sections or named list items from a configuration
+				file in place. Contexts are the dotted, name resolved contexts used by set.
+				The edited configuration is validated for the --aerospike-version, or the
+				version in the file metadata, unless --force is used, and is written back
+				in its format.`,
+		Example: `
+				# Remove the bar namespace and a service setting
+				asconfig unset aerospike.conf namespaces.bar service.proto-fd-max`,
+		RunE: runUnsetCommand,
+	}
+
+	res.Flags().AddFlagSet(getEditFlags())
+
+	res.Version = VERSION
+
+	return res
+}
+
+func newGetCmd() *cobra.Command {
+	res := &cobra.Command{
+		Use:   "get [flags] <path/to/config_file> <context>",
+		Short: "Print a configuration value.",
+		Long: `Get prints the value at a dotted, name resolved context of a configuration file.
+				Values are printed as is, and sections and lists as yaml, unless
+				--output-format json is used.`,
+		Example: `
+				# Print the replication factor of the test namespace
+				asconfig get aerospike.conf namespaces.test.replication-factor
+				# Print the test namespace as json
+				asconfig get --output-format json aerospike.conf namespaces.test`,
+		RunE: runGetCommand,
+	}
+
+	res.Flags().StringP("format", "F", "conf",
+		"The format of the source file. Valid options are: yaml, yml, json, toml, hcl, and conf.")
+	res.Flags().
+		String("output-format", outputFormatText, "The format of the value. Valid options are: text and json.")
+
+	res.Version = VERSION
+
+	return res
+}
+
+func runSetCommand(cmd *cobra.Command, args []string) error {
+	logger.Debug("Running set command")
+
+	if len(args) < editArgMin {
+		return errEditTooFewArgs
+	}
+
+	return editConfig(cmd, args[0], func(config map[string]any) error {
+		for _, assignment := range args[1:] {
+			context, val, ok := strings.Cut(assignment, "=")
+			if !ok || context == "" {
+				return fmt.Errorf("%w: %s", errInvalidEditAssignment, assignment)
+			}
+
+			if err := setContext(config, context, parseEditValue(val)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func runUnsetCommand(cmd *cobra.Command, args []string) error {
+	logger.Debug("Running unset command")
+
+	if len(args) < editArgMin {
+		return errEditTooFewArgs
+	}
+
+	return editConfig(cmd, args[0], func(config map[string]any) error {
+		for _, context := range args[1:] {
+			if err := unsetContext(config, context); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func runGetCommand(cmd *cobra.Command, args []string) error {
+	logger.Debug("Running get command")
+
+	if len(args) != getArgs {
+		return errGetArgs
+	}
+
+	outFmt, err := getOutputFormat(cmd, editOutputFormats...)
+	if err != nil {
+		return err
+	}
+
+	config, _, _, err := loadEditConfig(cmd, args[0])
+	if err != nil {
+		return err
+	}
+
+	val, err := getContext(config, args[1])
+	if err != nil {
+		return err
+	}
+
+	if outFmt == outputFormatJSON {
+		return renderStructured(cmd.OutOrStdout(), outFmt, val)
+	}
+
+	var out []byte
+
+	switch v := val.(type) {
+	case map[string]any, []any:
+		out, err = yaml.Marshal(v)
+	default:
+		out = []byte(fmt.Sprintln(v))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = cmd.OutOrStdout().Write(out)
+
+	return err
+}
+
+// loadEditConfig reads the config at srcPath as generic json types.
+func loadEditConfig(cmd *cobra.Command, srcPath string) (map[string]any, []byte, asConf.Format, error) {
+	srcFormat, err := getConfFileFormat(srcPath, cmd)
+	if err != nil {
+		return nil, nil, asConf.Invalid, err
+	}
+
+	fdata, err := os.ReadFile(srcPath)
+	if err != nil {
+		return nil, nil, asConf.Invalid, err
+	}
+
+	asconfig, err := conf.NewASConfigFromBytes(mgmtLibLogger, fdata, srcFormat)
+	if err != nil {
+		return nil, nil, asConf.Invalid, err
+	}
+
+	config, err := migrationConfigMap(asconfig)
+	if err != nil {
+		return nil, nil, asConf.Invalid, err
+	}
+
+	return config, fdata, srcFormat, nil
+}
+
+// editConfig applies edit to the config at srcPath, validates the result and
+// writes it back in the format of the source.
+func editConfig(cmd *cobra.Command, srcPath string, edit func(config map[string]any) error) error {
+	config, fdata, srcFormat, err := loadEditConfig(cmd, srcPath)
+	if err != nil {
+		return err
+	}
+
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Processing flag force value=%t", force)
+
+	version, err := getSourceVersion(cmd, fdata)
+	if err != nil && !force {
+		return err
+	}
+
+	if err := edit(config); err != nil {
+		return err
+	}
+
+	edited, err := asConf.NewMapAsConfig(mgmtLibLogger, config)
+	if err != nil {
+		return err
+	}
+
+	out, err := marshalEditedConfig(edited, fdata, srcFormat)
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		verrs, errValidate := newSourceValidator(
+			edited, out, srcFormat, version, conf.RuleOptions{},
+		).Validate()
+		if verrs != nil && verrs.HasErrors() {
+			return errors.Join(errEditValidationFailed, verrs)
+		}
+
+		if errValidate != nil {
+			return errValidate
+		}
+	}
+
+	stat, err := os.Stat(srcPath)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Writing edited config to: %s", srcPath)
+
+	return os.WriteFile(srcPath, out, stat.Mode().Perm())
+}
+
+// marshalEditedConfig writes the edited config in the format of its source src,
+// keeping the comments and ordering of yaml and conf sources and their metadata.
+func marshalEditedConfig(edited conf.ConfHandler, src []byte, srcFormat asConf.Format) ([]byte, error) {
+	marshaller := conf.NewConfigMarshaller(edited, srcFormat)
+
+	if srcFormat == asConf.YAML || srcFormat == asConf.AeroConfig {
+		layout, err := conf.NewSourceLayout(src, srcFormat)
+		if err != nil {
+			return nil, err
+		}
+
+		marshaller = marshaller.WithSourceLayout(layout)
+	}
+
+	out, err := marshaller.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	// only configs written by asconfig have metadata, comments that look like
+	// metadata outside of its block are kept in place by the layout
+	hasMetadata := bytes.Contains(src, []byte(metadata.Header))
+	if srcFormat == conf.JSON {
+		hasMetadata = bytes.Contains(src, []byte(`"`+metadata.JSONField+`"`))
+	}
+
+	if !hasMetadata {
+		return out, nil
+	}
+
+	mdata := map[string]string{metaKeyAsconfigVersion: VERSION}
+	if err := metadata.UnmarshalBlock(src, mdata); err != nil {
+		return nil, err
+	}
+
+	return addMetaData(out, srcFormat, nil, nil, mdata)
+}
+
+// parseEditValue parses a value as yaml, values that are not valid yaml are strings.
+func parseEditValue(val string) any {
+	var res any
+	if err := yaml.Unmarshal([]byte(val), &res); err != nil || res == nil {
+		return val
+	}
+
+	return res
+}
+
+// editTarget is the container of the value at a context.
+type editTarget struct {
+	// parent is the section or list that holds the value
+	parent any
+	// key is the key or the list item name of the value in parent
+	key string
+	// replace replaces parent in its own container
+	replace func(any)
+}
+
+// resolveContext finds the container of the value at a dotted, name resolved
+// context. Ex: namespaces.test.replication-factor. Missing sections and named
+// list items are created when create is set.
+func resolveContext(config map[string]any, context string, create bool) (editTarget, error) {
+	keys := strings.Split(context, ".")
+
+	var (
+		node    any = config
+		replace     = func(any) {}
+	)
+
+	for {
+		switch v := node.(type) {
+		case map[string]any:
+			if len(keys) == 1 {
+				return editTarget{parent: v, key: keys[0], replace: replace}, nil
+			}
+
+			key := keys[0]
+
+			child, ok := v[key]
+			if !ok {
+				if !create {
+					return editTarget{}, fmt.Errorf("%w: %s", errEditContextNotFound, context)
+				}
+
+				child = map[string]any{}
+				v[key] = child
+			}
+
+			node = child
+			keys = keys[1:]
+			replace = func(val any) { v[key] = val }
+		case []any:
+			item, n := findContextItem(v, keys)
+
+			if n == len(keys) {
+				return editTarget{parent: v, key: strings.Join(keys, "."), replace: replace}, nil
+			}
+
+			if item == nil {
+				if !create || !isNamedList(v) {
+					return editTarget{}, fmt.Errorf("%w: %s", errEditContextNotFound, context)
+				}
+
+				item = map[string]any{keyNameField: keys[0]}
+				n = 1
+
+				replace(append(v, item))
+			}
+
+			node = item
+			keys = keys[n:]
+			replace = func(any) {}
+		default:
+			return editTarget{}, fmt.Errorf("%w: %s", errEditContextNotSection, context)
+		}
+	}
+}
+
+// findContextItem returns the list item named by the longest prefix of keys,
+// since names such as logging file paths can contain dots, and the number of
+// keys in its name.
+func findContextItem(list []any, keys []string) (any, int) {
+	for n := len(keys); n > 0; n-- {
+		if item := findListItem(list, strings.Join(keys[:n], ".")); item != nil {
+			return item, n
+		}
+	}
+
+	return nil, 0
+}
+
+// listItemIndex returns the index of the section in list with the given name,
+// or the index name, or -1 if there is no such item.
+func listItemIndex(list []any, name string) int {
+	for i, item := range list {
+		if m, ok := item.(map[string]any); ok && m[keyNameField] == name {
+			return i
+		}
+	}
+
+	if i, err := strconv.Atoi(name); err == nil && i >= 0 && i < len(list) {
+		return i
+	}
+
+	return -1
+}
+
+// getContext returns the value at context.
+func getContext(config map[string]any, context string) (any, error) {
+	target, err := resolveContext(config, context, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		val any
+		ok  bool
+	)
+
+	switch parent := target.parent.(type) {
+	case map[string]any:
+		val, ok = parent[target.key]
+	case []any:
+		if i := listItemIndex(parent, target.key); i >= 0 {
+			val, ok = parent[i], true
+		}
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errEditContextNotFound, context)
+	}
+
+	return val, nil
+}
+
+// setContext sets the value at context.
+func setContext(config map[string]any, context string, val any) error {
+	target, err := resolveContext(config, context, true)
+	if err != nil {
+		return err
+	}
+
+	parent, ok := target.parent.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%w: %s", errEditNamedListItem, context)
+	}
+
+	parent[target.key] = val
+
+	return nil
+}
+
+// unsetContext removes the setting, section or named list item at context.
+func unsetContext(config map[string]any, context string) error {
+	target, err := resolveContext(config, context, false)
+	if err != nil {
+		return err
+	}
+
+	switch parent := target.parent.(type) {
+	case map[string]any:
+		if _, ok := parent[target.key]; ok {
+			delete(parent, target.key)
+			return nil
+		}
+	case []any:
+		if i := listItemIndex(parent, target.key); i >= 0 {
+			target.replace(slices.Delete(parent, i, i+1))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", errEditContextNotFound, context)
+}
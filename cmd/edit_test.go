@@ -0,0 +1,290 @@
+//go:build unit
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func testEditConfig() map[string]any {
+	return map[string]any{
+		"service": map[string]any{"proto-fd-max": int64(15000)},
+		"logging": []any{
+			map[string]any{"name": "/var/log/aerospike.log", "any": "info"},
+		},
+		"namespaces": []any{
+			map[string]any{
+				"name":               "test",
+				"replication-factor": int64(2),
+				"storage-engine":     map[string]any{"type": "memory"},
+			},
+		},
+	}
+}
+
+func TestEditContexts(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(config map[string]any) error
+		context string
+		want    any
+		wantErr error
+	}{
+		{
+			name:    "get setting",
+			context: "namespaces.test.storage-engine.type",
+			want:    "memory",
+		},
+		{
+			name:    "get named list item with dots",
+			context: "logging./var/log/aerospike.log.any",
+			want:    "info",
+		},
+		{
+			name:    "get missing context",
+			context: "namespaces.bar.replication-factor",
+			wantErr: errEditContextNotFound,
+		},
+		{
+			name:    "get through a value",
+			context: "service.proto-fd-max.x",
+			wantErr: errEditContextNotSection,
+		},
+		{
+			name: "set setting",
+			edit: func(config map[string]any) error {
+				return setContext(config, "namespaces.test.replication-factor", parseEditValue("3"))
+			},
+			context: "namespaces.test.replication-factor",
+			want:    3,
+		},
+		{
+			name: "set creates named list items and sections",
+			edit: func(config map[string]any) error {
+				return setContext(config, "namespaces.bar.storage-engine.files", parseEditValue("[/opt/a.dat]"))
+			},
+			context: "namespaces.bar",
+			want: map[string]any{
+				"name":           "bar",
+				"storage-engine": map[string]any{"files": []any{"/opt/a.dat"}},
+			},
+		},
+		{
+			name: "set named list item",
+			edit: func(config map[string]any) error {
+				return setContext(config, "namespaces.test", parseEditValue("{}"))
+			},
+			wantErr: errEditNamedListItem,
+		},
+		{
+			name: "unset setting",
+			edit: func(config map[string]any) error {
+				return unsetContext(config, "service.proto-fd-max")
+			},
+			context: "service",
+			want:    map[string]any{},
+		},
+		{
+			name: "unset named list item",
+			edit: func(config map[string]any) error {
+				return unsetContext(config, "logging./var/log/aerospike.log")
+			},
+			context: "logging",
+			want:    []any{},
+		},
+		{
+			name: "unset missing setting",
+			edit: func(config map[string]any) error {
+				return unsetContext(config, "service.cluster-name")
+			},
+			wantErr: errEditContextNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testEditConfig()
+
+			var err error
+			if tt.edit != nil {
+				err = tt.edit(config)
+			}
+
+			var got any
+			if err == nil {
+				got, err = getContext(config, tt.context)
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getContext() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunEditCommands(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	src := "# *** Aerospike Metadata Generated by Asconfig ***\n" +
+		"# aerospike-server-version: 7.0.0\n" +
+		"# *** End Aerospike Metadata ***\n\n" +
+		"service {\n    proto-fd-max    15000 # fds\n}\n\n" +
+		"logging {\n\n    console {\n        context any    info\n    }\n}\n\n" +
+		"namespace test {\n    replication-factor    2\n    storage-engine    memory\n}\n"
+
+	noMetadataSrc := "# owner: ops\nservice {\n    proto-fd-max    15000 # TODO: raise later\n}\n\n" +
+		"logging {\n\n    console {\n        context any    info\n    }\n}\n\n" +
+		"namespace test {\n    replication-factor    2\n    storage-engine    memory\n}\n"
+
+	tests := []struct {
+		name      string
+		newCmd    func() *cobra.Command
+		src       string
+		flags     []string
+		args      []string
+		wantErr   error
+		wantFile  string
+		wantValue string
+	}{
+		{
+			name:   "set",
+			newCmd: newSetCmd,
+			args:   []string{"namespaces.test.replication-factor=3"},
+			wantFile: "# *** Aerospike Metadata Generated by Asconfig ***\n" +
+				"# aerospike-server-version: 7.0.0\n" +
+				"# asconfig-version: " + VERSION + "\n" +
+				"# *** End Aerospike Metadata ***\n\n" +
+				"service {\n    proto-fd-max    15000 # fds\n}\n\n" +
+				"logging {\n\n    console {\n        context any    info\n    }\n}\n\n" +
+				"namespace test {\n    replication-factor    3\n    storage-engine    memory\n}\n",
+		},
+		{
+			name:   "set without metadata",
+			newCmd: newSetCmd,
+			src:    noMetadataSrc,
+			flags:  []string{"--aerospike-version", "7.0.0"},
+			args:   []string{"namespaces.test.replication-factor=3"},
+			wantFile: "# owner: ops\nservice {\n    proto-fd-max    15000 # TODO: raise later\n}\n\n" +
+				"logging {\n\n    console {\n        context any    info\n    }\n}\n\n" +
+				"namespace test {\n    replication-factor    3\n    storage-engine    memory\n}\n",
+		},
+		{
+			name:     "set invalid value",
+			newCmd:   newSetCmd,
+			args:     []string{"namespaces.test.replication-factor=many"},
+			wantErr:  errEditValidationFailed,
+			wantFile: src,
+		},
+		{
+			name:   "set only checks the schema",
+			newCmd: newSetCmd,
+			args:   []string{"namespaces.test.strong-consistency=true", "namespaces.test.default-ttl=100"},
+			wantFile: "# *** Aerospike Metadata Generated by Asconfig ***\n" +
+				"# aerospike-server-version: 7.0.0\n" +
+				"# asconfig-version: " + VERSION + "\n" +
+				"# *** End Aerospike Metadata ***\n\n" +
+				"service {\n    proto-fd-max    15000 # fds\n}\n\n" +
+				"logging {\n\n    console {\n        context any    info\n    }\n}\n\n" +
+				"namespace test {\n    replication-factor    2\n    storage-engine    memory\n" +
+				"    default-ttl    100\n    strong-consistency    true\n}\n",
+		},
+		{
+			name:     "invalid assignment",
+			newCmd:   newSetCmd,
+			args:     []string{"namespaces.test.replication-factor"},
+			wantErr:  errInvalidEditAssignment,
+			wantFile: src,
+		},
+		{
+			name:   "unset",
+			newCmd: newUnsetCmd,
+			args:   []string{"service.proto-fd-max"},
+			wantFile: "# *** Aerospike Metadata Generated by Asconfig ***\n" +
+				"# aerospike-server-version: 7.0.0\n" +
+				"# asconfig-version: " + VERSION + "\n" +
+				"# *** End Aerospike Metadata ***\n\n" +
+				"logging {\n\n    console {\n        context any    info\n    }\n}\n\n" +
+				"namespace test {\n    replication-factor    2\n    storage-engine    memory\n}\n",
+		},
+		{
+			name:      "get",
+			newCmd:    newGetCmd,
+			args:      []string{"namespaces.test.replication-factor"},
+			wantFile:  src,
+			wantValue: "2\n",
+		},
+		{
+			name:     "get json",
+			newCmd:   newGetCmd,
+			flags:    []string{"--output-format", "json"},
+			args:     []string{"namespaces.test"},
+			wantFile: src,
+			wantValue: "{\n  \"name\": \"test\",\n  \"replication-factor\": 2,\n" +
+				"  \"storage-engine\": {\n    \"type\": \"memory\"\n  }\n}\n",
+		},
+		{
+			name:    "get too many arguments",
+			newCmd:  newGetCmd,
+			args:    []string{"service", "logging"},
+			wantErr: errGetArgs,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := src
+			if tt.src != "" {
+				data = tt.src
+			}
+
+			path := filepath.Join(t.TempDir(), "aerospike.conf")
+			if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			cmd := tt.newCmd()
+			if err := cmd.ParseFlags(tt.flags); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+
+			var out bytes.Buffer
+
+			cmd.SetOut(&out)
+			cmd.SetErr(io.Discard)
+
+			err := cmd.RunE(cmd, append([]string{path}, tt.args...))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantFile != "" {
+				got, errRead := os.ReadFile(path)
+				if errRead != nil {
+					t.Fatalf("Failed to read config: %v", errRead)
+				}
+
+				if string(got) != tt.wantFile {
+					t.Errorf("file = %q, want %q", got, tt.wantFile)
+				}
+			}
+
+			if out.String() != tt.wantValue {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantValue)
+			}
+		})
+	}
+}
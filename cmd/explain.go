@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aerospike/asconfig/schema"
+)
+
+const explainArgs = 1
+
+var (
+	errExplainArgs      = fmt.Errorf("explain expects %d argument: <config-path>", explainArgs)
+	errUnknownParameter = errors.New("unknown configuration parameter")
+)
+
+func newExplainCmd() *cobra.Command {
+	res := &cobra.Command{
+		Use:   "explain [flags] <config-path>",
+		Short: "Explain an Aerospike configuration parameter.",
+		Long: `Explain prints what the configuration schema of an Aerospike server version
+				knows about a parameter: its type, default, allowed values, bounds,
+				whether it is dynamic and whether it is enterprise only.
+				It also lists the versions in which the parameter was introduced,
+				changed or removed.
+				Parameters are dotted yaml paths. Names of list items, such as namespace
+				names, can be included so that the contexts of validation errors can be used.
+				The --aerospike-version defaults to the newest supported version.`,
+		Example: `
+				# Explain the write block size of namespace storage engines in 7.1
+				asconfig explain namespaces.storage-engine.write-block-size --aerospike-version 7.1.0
+				# Contexts with namespace names can be used
+				asconfig explain namespaces.test.replication-factor`,
+		RunE: runExplainCommand,
+	}
+
+	res.Flags().StringP("aerospike-version", "a", "",
+		"Aerospike server version to explain the parameter for. Ex: 7.1.0. Defaults to the newest supported version.")
+
+	res.Version = VERSION
+
+	return res
+}
+
+func runExplainCommand(cmd *cobra.Command, args []string) error {
+	logger.Debug("Running explain command")
+
+	if len(args) != explainArgs {
+		return errExplainArgs
+	}
+
+	schemaMap, err := schema.NewSchemaMap()
+	if err != nil {
+		return fmt.Errorf("failed to load schema map: %w", err)
+	}
+
+	versions := schemaVersions(schemaMap)
+
+	version, err := cmd.Flags().GetString("aerospike-version")
+	if err != nil {
+		return err
+	}
+
+	if version == "" {
+		version = versions[len(versions)-1]
+	}
+
+	logger.Debugf("Processing flag aerospike-version value=%s", version)
+
+	node, err := loadSchema(schemaMap, version)
+	if err != nil {
+		return err
+	}
+
+	path, err := findSettingPath(schemaMap, versions, args[0])
+	if err != nil {
+		return err
+	}
+
+	history, err := settingHistory(schemaMap, path)
+	if err != nil {
+		return err
+	}
+
+	prop, found := schemaPropertyAt(node, path)
+
+	return renderExplanation(cmd.OutOrStdout(), path, version, prop.node, found, versions[0], history)
+}
+
+// findSettingPath returns the schema path of param in the newest of versions
+// that defines it.
+func findSettingPath(schemaMap schema.SchemaMap, versions []string, param string) (string, error) {
+	for i := len(versions) - 1; i >= 0; i-- {
+		node, err := loadSchema(schemaMap, versions[i])
+		if err != nil {
+			return "", err
+		}
+
+		if path, ok := schemaSettingPath(node, param); ok {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", errUnknownParameter, param)
+}
+
+// renderExplanation writes the schema details of the setting at path and the
+// versions in which it was introduced, changed or removed.
+func renderExplanation(
+	w io.Writer,
+	path, version string,
+	node map[string]any,
+	found bool,
+	oldest string,
+	history []settingEvent,
+) error {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s (Aerospike %s)\n", path, version))
+
+	if found {
+		sb.WriteString(formatValueDetails(explainDetails(node)))
+	} else {
+		sb.WriteString(fmt.Sprintf("     → Not supported by Aerospike %s\n", version))
+	}
+
+	var changed, removed []string
+
+	for _, event := range history {
+		switch event.Kind {
+		case settingAdded:
+			introduced := event.Version
+			if event.Version == oldest {
+				introduced += " or earlier"
+			}
+
+			sb.WriteString(fmt.Sprintf("Introduced: %s\n", introduced))
+		case settingChanged:
+			fields := make([]string, 0, len(event.Changes))
+			for _, change := range event.Changes {
+				fields = append(fields, change.Field)
+			}
+
+			changed = append(changed, fmt.Sprintf("%s (%s)", event.Version, strings.Join(fields, ", ")))
+		case settingRemoved:
+			removed = append(removed, event.Version)
+		}
+	}
+
+	if len(changed) > 0 {
+		sb.WriteString(fmt.Sprintf("Changed: %s\n", strings.Join(changed, ", ")))
+	}
+
+	if len(removed) > 0 {
+		sb.WriteString(fmt.Sprintf("Removed: %s\n", strings.Join(removed, ", ")))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// explainDetails returns the schema fields of a setting to display. Sections
+// list the names of their settings instead of their definitions.
+func explainDetails(node map[string]any) map[string]any {
+	res := maps.Clone(node)
+
+	if items, ok := schemaItems(node, ""); ok {
+		node = items.node
+	}
+
+	if props := schemaProperties(node, ""); len(props) > 0 {
+		names := make([]any, 0, len(props))
+		for _, name := range slices.Sorted(maps.Keys(props)) {
+			names = append(names, name)
+		}
+
+		res[propertiesField] = names
+		delete(res, itemsField)
+	}
+
+	for _, keyword := range schemaCombinators {
+		delete(res, keyword)
+	}
+
+	return res
+}
@@ -0,0 +1,190 @@
+//go:build unit
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aerospike/asconfig/schema"
+)
+
+func TestSchemaSettingPath(t *testing.T) {
+	schemaMap, err := schema.NewSchemaMap()
+	if err != nil {
+		t.Fatalf("Failed to load schema map: %v", err)
+	}
+
+	node, err := loadSchema(schemaMap, "7.1.0")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	tests := []struct {
+		param  string
+		want   string
+		wantOK bool
+	}{
+		{param: "service.proto-fd-max", want: "service.proto-fd-max", wantOK: true},
+		{param: "namespaces.storage-engine.write-block-size", want: "namespaces.storage-engine.write-block-size", wantOK: true},
+		{param: "namespaces.test.replication-factor", want: "namespaces.replication-factor", wantOK: true},
+		{param: "namespaces.memory-size"},
+		{param: "service.test.proto-fd-max"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.param, func(t *testing.T) {
+			got, ok := schemaSettingPath(node, tt.param)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("schemaSettingPath() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSettingHistory(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	schemaMap, err := schema.NewSchemaMap()
+	if err != nil {
+		t.Fatalf("Failed to load schema map: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want []settingEvent
+	}{
+		{
+			path: "service.proto-fd-max",
+			want: []settingEvent{
+				{
+					Version: "6.4.0",
+					Kind:    settingAdded,
+					Changes: []settingFieldChange{
+						{Field: "type", New: "integer"},
+						{Field: "default", New: float64(15000)},
+						{Field: "minimum", New: float64(0)},
+						{Field: "maximum", New: float64(2147483647)},
+						{Field: "dynamic", New: true},
+					},
+				},
+				{
+					Version: "7.1.0",
+					Kind:    settingChanged,
+					Changes: []settingFieldChange{{Field: "default", Old: float64(15000), New: float64(50000)}},
+				},
+			},
+		},
+		{
+			path: "namespaces.memory-size",
+			want: []settingEvent{
+				{
+					Version: "6.4.0",
+					Kind:    settingAdded,
+					Changes: []settingFieldChange{
+						{Field: "type", New: "integer"},
+						{Field: "default", New: float64(4294967296)},
+						{Field: "minimum", New: float64(0)},
+						{Field: "dynamic", New: false},
+					},
+				},
+				{Version: "7.0.0", Kind: settingRemoved},
+			},
+		},
+		{
+			path: "service.unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := settingHistory(schemaMap, tt.path)
+			if err != nil {
+				t.Fatalf("settingHistory() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("settingHistory() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunExplainCommand(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		flags      []string
+		args       []string
+		wantErr    error
+		wantOutput []string
+	}{
+		{
+			name:  "setting",
+			flags: []string{"--aerospike-version", "7.1.0"},
+			args:  []string{"namespaces.storage-engine.write-block-size"},
+			wantOutput: []string{
+				"namespaces.storage-engine.write-block-size (Aerospike 7.1.0)",
+				"Default: 1048576", "Minimum: 1024", "Maximum: 8388608", "Type: integer",
+				"Introduced: 6.4.0 or earlier",
+			},
+		},
+		{
+			name:       "changed setting",
+			args:       []string{"service.proto-fd-max"},
+			wantOutput: []string{"(Aerospike 8.1.0)", "Default: 50000", "Changed: 7.1.0 (default)"},
+		},
+		{
+			name:       "removed setting",
+			args:       []string{"namespaces.test.memory-size"},
+			wantOutput: []string{"Not supported by Aerospike 8.1.0", "Removed: 7.0.0"},
+		},
+		{
+			name:    "unknown setting",
+			args:    []string{"service.unknown"},
+			wantErr: errUnknownParameter,
+		},
+		{
+			name:    "unsupported version",
+			flags:   []string{"--aerospike-version", "5.0.0"},
+			args:    []string{"service.proto-fd-max"},
+			wantErr: errUnsupportedAerospikeVersion,
+		},
+		{
+			name:    "no parameter",
+			wantErr: errExplainArgs,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newExplainCmd()
+
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+
+			if err := cmd.ParseFlags(tt.flags); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+
+			err := cmd.RunE(cmd, tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunE() error = %v, want %v", err, tt.wantErr)
+			}
+
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("RunE() output = %s, want it to contain %q", out.String(), want)
+				}
+			}
+		})
+	}
+}
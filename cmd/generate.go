@@ -68,7 +68,7 @@ func newGenerateCmd() *cobra.Command {
 	res.Flags().StringP("output", "o", os.Stdout.Name(),
 		flags.DefaultWrapHelpString("File path to write output to"))
 	res.Flags().StringP("format", "F", "conf",
-		flags.DefaultWrapHelpString("The format of the destination file(s). Valid options are: yaml, yml, and conf."))
+		flags.DefaultWrapHelpString("The format of the destination file(s). Valid options are: yaml, yml, json, toml, hcl, and conf."))
 
 	return res
 }
@@ -122,14 +122,12 @@ func runGenerateCommand(cmd *cobra.Command, aerospikeFlags *flags.AerospikeFlags
 		metaKeyAerospikeVersion: generatedConf.Version,
 		metaKeyAsconfigVersion:  VERSION,
 	}
-	// prepend metadata to the config output
-	mtext, err := genMetaDataText(fdata, disclaimer, mdata)
+	// add metadata to the config output
+	fdata, err = addMetaData(fdata, outFormat, fdata, disclaimer, mdata)
 	if err != nil {
 		return err
 	}
 
-	fdata = append(mtext, fdata...)
-
 	var outFile *os.File
 	if outputPath == os.Stdout.Name() {
 		outFile = os.Stdout
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aerospike/asconfig/schema"
+)
+
+const historyArgs = 1
+
+var (
+	errHistoryArgs       = fmt.Errorf("history expects %d argument: <config-path>", historyArgs)
+	historyOutputFormats = []string{outputFormatText, outputFormatJSON}
+)
+
+func newHistoryCmd() *cobra.Command {
+	res := &cobra.Command{
+		Use:   "history [flags] <config-path>",
+		Short: "Show the history of a configuration parameter across Aerospike versions.",
+		Long: `History walks the configuration schemas of every supported Aerospike server
+				version, oldest first, and prints a timeline of when a parameter appeared,
+				disappeared, and changed its type, default, bounds, allowed values, or
+				whether it is dynamic or enterprise only.
+				Parameters are dotted yaml paths, like in the explain command. Names of list
+				items, such as namespace names, can be included.`,
+		Example: `
+				# When did flush-size appear and when did its definition change
+				asconfig history namespaces.storage-engine.flush-size
+				# The timeline as json
+				asconfig history --output-format json service.proto-fd-max`,
+		RunE: runHistoryCommand,
+	}
+
+	res.Flags().
+		String("output-format", outputFormatText, "The format of the timeline. Valid options are: text and json.")
+
+	res.Version = VERSION
+
+	return res
+}
+
+// settingTimeline is the history of a setting across server versions.
+type settingTimeline struct {
+	Path   string         `json:"path"`
+	Events []settingEvent `json:"events"`
+}
+
+func runHistoryCommand(cmd *cobra.Command, args []string) error {
+	logger.Debug("Running history command")
+
+	if len(args) != historyArgs {
+		return errHistoryArgs
+	}
+
+	outFmt, err := getOutputFormat(cmd, historyOutputFormats...)
+	if err != nil {
+		return err
+	}
+
+	schemaMap, err := schema.NewSchemaMap()
+	if err != nil {
+		return fmt.Errorf("failed to load schema map: %w", err)
+	}
+
+	path, err := findSettingPath(schemaMap, schemaVersions(schemaMap), args[0])
+	if err != nil {
+		return err
+	}
+
+	events, err := settingHistory(schemaMap, path)
+	if err != nil {
+		return err
+	}
+
+	return renderSettingTimeline(cmd.OutOrStdout(), outFmt, settingTimeline{Path: path, Events: events})
+}
+
+// renderSettingTimeline writes the timeline of a setting in outFmt.
+func renderSettingTimeline(w io.Writer, outFmt string, timeline settingTimeline) error {
+	if outFmt == outputFormatJSON {
+		return renderStructured(w, outFmt, timeline)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(timeline.Path + "\n")
+
+	for _, event := range timeline.Events {
+		changes := make([]string, 0, len(event.Changes))
+
+		for _, change := range event.Changes {
+			if event.Kind == settingAdded {
+				changes = append(changes, fmt.Sprintf("%s: %s", formatKeyName(change.Field), historyValue(change.New)))
+				continue
+			}
+
+			changes = append(changes, fmt.Sprintf("%s: %s → %s",
+				formatKeyName(change.Field), historyValue(change.Old), historyValue(change.New)))
+		}
+
+		line := fmt.Sprintf("  %-8s %-8s %s", event.Version, event.Kind, strings.Join(changes, ", "))
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// historyValue formats a schema field value of a timeline.
+func historyValue(val any) string {
+	if val == nil {
+		return "none"
+	}
+
+	if s, ok := val.(string); ok && s == "" {
+		return `""`
+	}
+
+	return formatValue(val)
+}
@@ -0,0 +1,109 @@
+//go:build unit
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRunHistoryCommand(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		flags   []string
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "added and changed",
+			args: []string{"namespaces.storage-engine.flush-size"},
+			want: "namespaces.storage-engine.flush-size\n" +
+				"  7.0.0    added    Type: integer, Default: 1048576, Dynamic: No\n" +
+				"  8.0.0    changed  Maximum: none → 8388608\n",
+		},
+		{
+			name: "removed with a namespace context",
+			args: []string{"namespaces.test.memory-size"},
+			want: "namespaces.memory-size\n" +
+				"  6.4.0    added    Type: integer, Default: 4294967296, Minimum: 0, Dynamic: No\n" +
+				"  7.0.0    removed\n",
+		},
+		{
+			name:  "json",
+			flags: []string{"--output-format", "json"},
+			args:  []string{"service.disable-odirect"},
+			want: `{
+  "path": "service.disable-odirect",
+  "events": [
+    {
+      "version": "6.4.0",
+      "kind": "added",
+      "changes": [
+        {
+          "field": "type",
+          "new": "boolean"
+        },
+        {
+          "field": "default",
+          "new": false
+        },
+        {
+          "field": "dynamic",
+          "new": false
+        }
+      ]
+    },
+    {
+      "version": "8.0.0",
+      "kind": "removed"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:    "unknown setting",
+			args:    []string{"service.unknown"},
+			wantErr: errUnknownParameter,
+		},
+		{
+			name:    "invalid output format",
+			flags:   []string{"--output-format", "yaml"},
+			args:    []string{"service.proto-fd-max"},
+			wantErr: errInvalidOutputFormat,
+		},
+		{
+			name:    "too many arguments",
+			args:    []string{"service.proto-fd-max", "service.cluster-name"},
+			wantErr: errHistoryArgs,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newHistoryCmd()
+
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+
+			if err := cmd.ParseFlags(tt.flags); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+
+			err := cmd.RunE(cmd, tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunE() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if out.String() != tt.want {
+				t.Errorf("RunE() output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,530 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	lib "github.com/aerospike/aerospike-management-lib"
+	"github.com/spf13/cobra"
+
+	"github.com/aerospike/asconfig/conf"
+	"github.com/aerospike/asconfig/schema"
+)
+
+const (
+	lintArgMax = 1
+
+	// lint check ids.
+	lintCheckDeprecated       = "deprecated"
+	lintCheckRedundantDefault = "redundant-default"
+	lintCheckDangerous        = "dangerous"
+	lintCheckRemovedNextMajor = "removed-in-next-major"
+
+	// failOnNone never fails the lint command because of findings.
+	failOnNone = "none"
+
+	deprecatedKeyword = "deprecated"
+)
+
+var (
+	errLintTooManyArguments = fmt.Errorf("lint accepts a maximum of %d argument", lintArgMax)
+	errLintFindings         = errors.New("lint findings at or above the fail-on severity")
+	errInvalidFailOn        = errors.New("invalid fail-on flag")
+)
+
+// severityRanks orders severities from least to most severe.
+var severityRanks = map[conf.Severity]int{
+	conf.SeverityInfo:    1,
+	conf.SeverityWarning: 2,
+	conf.SeverityError:   3,
+}
+
+// dangerousSetting is a legal setting that is risky when enabled.
+type dangerousSetting struct {
+	severity conf.Severity
+	reason   string
+}
+
+// dangerousSettings are settings that are known to risk data loss or
+// availability when they are set to true.
+var dangerousSettings = map[string]dangerousSetting{
+	"disable-odirect": {
+		severity: conf.SeverityError,
+		reason:   "writes bypass O_DIRECT and may be lost from the page cache on power failure",
+	},
+	"strong-consistency-allow-expunge": {
+		severity: conf.SeverityError,
+		reason:   "allows records to be expunged which can lose committed writes",
+	},
+	"cold-start-empty": {
+		severity: conf.SeverityWarning,
+		reason:   "data on storage devices is ignored on cold start",
+	},
+	"allow-ttl-without-nsup": {
+		severity: conf.SeverityWarning,
+		reason:   "records with a TTL will not expire or be evicted while nsup is disabled",
+	},
+}
+
+// lintIgnoredKeys identify list items and typed sections rather than configure them.
+var lintIgnoredKeys = map[string]struct{}{
+	keyNameField: {},
+	keyTypeField: {},
+}
+
+func newLintCmd() *cobra.Command {
+	res := &cobra.Command{
+		Use:   "lint [flags] [path/to/config_file]",
+		Short: "Report deprecated, redundant and risky settings in an Aerospike configuration file.",
+		Long: `Lint reports settings that are legal for the target Aerospike server version
+				but are likely mistakes. Each finding has a severity of error, warning or info.
+				Checks:
+				  deprecated: the setting is deprecated in the target version (warning)
+				  removed-in-next-major: the setting is removed in the next major version (warning)
+				  dangerous: the setting is known to risk data loss, Ex: disable-odirect (error or warning)
+				  redundant-default: the setting is set to its default value (info)
+				The command fails if any finding is at least as severe as --fail-on.
+				If a file path is not provided, lint reads from stdin.`,
+		Example: `
+				# Lint a config file for Aerospike 7.0
+				asconfig lint --aerospike-version 7.0.0 aerospike.conf
+				# Only fail on warnings and errors, output json
+				asconfig lint --fail-on warning --output-format json aerospike.yaml`,
+		RunE: runLintCommand,
+	}
+
+	res.Flags().AddFlagSet(getCommonFlags())
+	res.Flags().
+		StringP("format", "F", "conf", "The format of the source file. Valid options are: yaml, yml, json, toml, hcl, and conf.")
+	res.Flags().
+		String("output-format", outputFormatText, "The format of the lint findings. Valid options are: text and json.")
+	res.Flags().
+		String("fail-on", string(conf.SeverityError),
+			"The lowest finding severity that fails the command. Valid options are: error, warning, info, and none.")
+
+	res.Version = VERSION
+
+	return res
+}
+
+// lintFinding is a single problem found by lint.
+type lintFinding struct {
+	Context     string        `json:"context"`
+	Check       string        `json:"check"`
+	Severity    conf.Severity `json:"severity"`
+	Description string        `json:"description"`
+	Value       any           `json:"value,omitempty"`
+	Line        int           `json:"line,omitempty"`
+	Column      int           `json:"column,omitempty"`
+}
+
+// lintReport is the machine readable document written by lint.
+type lintReport struct {
+	Source   string        `json:"source"`
+	Version  string        `json:"aerospike-version"`
+	Findings []lintFinding `json:"findings"`
+}
+
+func runLintCommand(cmd *cobra.Command, args []string) error {
+	logger.Debug("Running lint command")
+
+	if len(args) > lintArgMax {
+		return errLintTooManyArguments
+	}
+
+	outFmt, err := getOutputFormat(cmd, outputFormatText, outputFormatJSON)
+	if err != nil {
+		return err
+	}
+
+	failOn, err := getFailOn(cmd)
+	if err != nil {
+		return err
+	}
+
+	// read stdin by default
+	srcPath := os.Stdin.Name()
+	if len(args) > 0 {
+		srcPath = args[0]
+	}
+
+	srcFormat, err := getConfFileFormat(srcPath, cmd)
+	if err != nil {
+		return err
+	}
+
+	fdata, err := os.ReadFile(srcPath)
+	if err != nil {
+		return err
+	}
+
+	version, err := getSourceVersion(cmd, fdata)
+	if err != nil {
+		return err
+	}
+
+	asconfig, err := conf.NewASConfigFromBytes(mgmtLibLogger, fdata, srcFormat)
+	if err != nil {
+		return err
+	}
+
+	schemaMap, err := schema.NewSchemaMap()
+	if err != nil {
+		return fmt.Errorf("failed to load schema map: %w", err)
+	}
+
+	findings, err := lintConfig(asconfig, schemaMap, version)
+	if err != nil {
+		return err
+	}
+
+	if positions, errPos := conf.NewSourcePositions(fdata, srcFormat); errPos == nil {
+		for i := range findings {
+			if pos, ok := positions.Lookup(findings[i].Context); ok {
+				findings[i].Line = pos.Line
+				findings[i].Column = pos.Column
+			}
+		}
+	} else {
+		logger.Debugf("Unable to determine source positions: %v", errPos)
+	}
+
+	report := lintReport{Source: srcPath, Version: version, Findings: findings}
+	if err := renderLintReport(cmd.OutOrStdout(), outFmt, report); err != nil {
+		return err
+	}
+
+	if lintFails(findings, failOn) {
+		return errors.Join(errLintFindings, ErrSilent)
+	}
+
+	return nil
+}
+
+// getFailOn returns the value of the --fail-on flag.
+func getFailOn(cmd *cobra.Command) (string, error) {
+	failOn, err := cmd.Flags().GetString("fail-on")
+	if err != nil {
+		return "", err
+	}
+
+	logger.Debugf("Processing flag fail-on value=%s", failOn)
+
+	failOn = strings.ToLower(failOn)
+	if _, ok := severityRanks[conf.Severity(failOn)]; !ok && failOn != failOnNone {
+		return "", fmt.Errorf("%w: %s", errInvalidFailOn, failOn)
+	}
+
+	return failOn, nil
+}
+
+// getSourceVersion returns the server version from the --aerospike-version
+// flag, or from the metadata of src if the flag is not set.
+func getSourceVersion(cmd *cobra.Command, src []byte) (string, error) {
+	version, err := cmd.Flags().GetString("aerospike-version")
+	if err != nil {
+		return "", err
+	}
+
+	if version == "" {
+		version, err = getMetaDataItemOptional(src, metaKeyAerospikeVersion)
+		if err != nil {
+			return "", errors.Join(errMissingAerospikeVersion, err)
+		}
+	}
+
+	if version == "" {
+		return "", errMissingAerospikeVersion
+	}
+
+	logger.Debugf("Processing flag aerospike-version value=%s", version)
+
+	return version, nil
+}
+
+// lintFails reports whether any finding is at least as severe as failOn.
+func lintFails(findings []lintFinding, failOn string) bool {
+	threshold, ok := severityRanks[conf.Severity(failOn)]
+	if !ok {
+		return false
+	}
+
+	for _, f := range findings {
+		if severityRanks[f.Severity] >= threshold {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lintConfig checks every setting in asconfig against the schema for version.
+// Findings are sorted by context and check.
+func lintConfig(asconfig conf.ConfHandler, schemaMap schema.SchemaMap, version string) ([]lintFinding, error) {
+	schemaCur, err := loadSchema(schemaMap, version)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, nextMajor, err := removedInNextMajor(schemaMap, schemaCur, version)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonConfigStr, err := json.Marshal(asconfig.ToMap())
+	if err != nil {
+		return nil, err
+	}
+
+	jsonConfig := map[string]any{}
+	if err := json.Unmarshal(jsonConfigStr, &jsonConfig); err != nil {
+		return nil, err
+	}
+
+	findings := []lintFinding{}
+
+	walkConfigSchema(jsonConfig, schemaProperty{node: schemaCur}, "",
+		func(context, key string, value any, prop schemaProperty) {
+			findings = append(findings, lintSetting(context, key, value, prop)...)
+
+			if isRemovedPointer(removed, prop.pointer) {
+				findings = append(findings, lintFinding{
+					Context:     context,
+					Check:       lintCheckRemovedNextMajor,
+					Severity:    conf.SeverityWarning,
+					Description: fmt.Sprintf("%s is removed in %s", key, nextMajor),
+				})
+			}
+		},
+	)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Context != findings[j].Context {
+			return findings[i].Context < findings[j].Context
+		}
+
+		return findings[i].Check < findings[j].Check
+	})
+
+	return findings, nil
+}
+
+// lintSetting runs the checks that only need a setting and its schema definition.
+func lintSetting(context, key string, value any, prop schemaProperty) []lintFinding {
+	var res []lintFinding
+
+	if isDeprecated(prop.node) {
+		res = append(res, lintFinding{
+			Context:     context,
+			Check:       lintCheckDeprecated,
+			Severity:    conf.SeverityWarning,
+			Description: key + " is deprecated",
+		})
+	}
+
+	if danger, ok := dangerousSettings[key]; ok {
+		if enabled, _ := value.(bool); enabled {
+			res = append(res, lintFinding{
+				Context:     context,
+				Check:       lintCheckDangerous,
+				Severity:    danger.severity,
+				Description: fmt.Sprintf("%s is enabled, %s", key, danger.reason),
+				Value:       value,
+			})
+		}
+	}
+
+	if def, ok := prop.node["default"]; ok && reflect.DeepEqual(def, value) {
+		res = append(res, lintFinding{
+			Context:     context,
+			Check:       lintCheckRedundantDefault,
+			Severity:    conf.SeverityInfo,
+			Description: fmt.Sprintf("%s is set to its default value", key),
+			Value:       value,
+		})
+	}
+
+	return res
+}
+
+// isDeprecated reports whether a schema property is marked deprecated,
+// either with the deprecated keyword or by a description that starts with
+// "Deprecated". Descriptions that only mention a deprecated setting don't count.
+func isDeprecated(node map[string]any) bool {
+	if deprecated, _ := node[deprecatedKeyword].(bool); deprecated {
+		return true
+	}
+
+	description, _ := node[descriptionField].(string)
+
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(description)), deprecatedKeyword)
+}
+
+// lintVisitFunc is called for every setting found by walkConfigSchema.
+type lintVisitFunc func(context, key string, value any, prop schemaProperty)
+
+// walkConfigSchema calls visit for every setting in config that is defined by the
+// schema node prop. Sections are descended into and list items are identified by
+// name. Settings that are not in the schema are left to validation.
+func walkConfigSchema(config map[string]any, prop schemaProperty, context string, visit lintVisitFunc) {
+	props := schemaProperties(prop.node, prop.pointer)
+
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := config[key]
+
+		child, ok := props[key]
+		if !ok {
+			continue
+		}
+
+		childCtx := conf.JoinContext(context, key)
+
+		switch val := value.(type) {
+		case map[string]any:
+			walkConfigSchema(val, child, childCtx, visit)
+		case []any:
+			items, ok := schemaItems(child.node, child.pointer)
+			if !ok || !isSectionList(val) {
+				visitSetting(visit, childCtx, key, value, child)
+				continue
+			}
+
+			for i, item := range val {
+				itemMap := item.(map[string]any) //nolint:errcheck // checked by isSectionList
+
+				name, ok := itemMap[keyNameField].(string)
+				if !ok {
+					name = strconv.Itoa(i)
+				}
+
+				walkConfigSchema(itemMap, items, conf.JoinContext(childCtx, name), visit)
+			}
+		default:
+			visitSetting(visit, childCtx, key, value, child)
+		}
+	}
+}
+
+// visitSetting calls visit unless key identifies its section rather than configuring it.
+func visitSetting(visit lintVisitFunc, context, key string, value any, prop schemaProperty) {
+	if _, ok := lintIgnoredKeys[key]; ok {
+		return
+	}
+
+	visit(context, key, value, prop)
+}
+
+// isSectionList reports whether list is a non empty list of sections.
+func isSectionList(list []any) bool {
+	if len(list) == 0 {
+		return false
+	}
+
+	for _, item := range list {
+		if _, ok := item.(map[string]any); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// removedInNextMajor returns the json pointers of the schema properties of schemaCur that are
+// removed in the first release of the next major version, and that version.
+// No pointers are returned if there is no newer major version.
+func removedInNextMajor(
+	schemaMap schema.SchemaMap,
+	schemaCur map[string]any,
+	version string,
+) ([]string, string, error) {
+	nextMajor, err := nextMajorVersion(schemaVersions(schemaMap), version)
+	if err != nil || nextMajor == "" {
+		return nil, "", err
+	}
+
+	schemaNext, err := loadSchema(schemaMap, nextMajor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	summary, err := compareSchemas(schemaCur, schemaNext, version, nextMajor)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to compare schemas: %w", err)
+	}
+
+	var res []string
+
+	for _, section := range summary.Sections {
+		for _, change := range section.Removals {
+			res = append(res, change.Path)
+		}
+	}
+
+	return res, nextMajor, nil
+}
+
+// nextMajorVersion returns the first of the sorted versions with a
+// higher major version than version, or an empty string if there is none.
+func nextMajorVersion(versions []string, version string) (string, error) {
+	major, _, _ := strings.Cut(version, ".")
+
+	for _, v := range versions {
+		vMajor, _, _ := strings.Cut(v, ".")
+
+		cmp, err := lib.CompareVersions(vMajor+".0.0", major+".0.0")
+		if err != nil {
+			return "", err
+		}
+
+		if cmp > 0 {
+			return v, nil
+		}
+	}
+
+	return "", nil
+}
+
+// isRemovedPointer reports whether pointer, or one of its parents, is in removed.
+func isRemovedPointer(removed []string, pointer string) bool {
+	for _, r := range removed {
+		if pointer == r || strings.HasPrefix(pointer, r+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// renderLintReport writes report to w in the requested output format.
+func renderLintReport(w io.Writer, outFmt string, report lintReport) error {
+	if outFmt == outputFormatJSON {
+		return renderStructured(w, outFmt, report)
+	}
+
+	for _, f := range report.Findings {
+		location := f.Context
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s (line %d, column %d)", f.Context, f.Line, f.Column)
+		}
+
+		if _, err := fmt.Fprintf(w, "%s: %s: %s [%s]\n", f.Severity, location, f.Description, f.Check); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
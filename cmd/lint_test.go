@@ -0,0 +1,273 @@
+//go:build unit
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+
+	"github.com/aerospike/asconfig/conf"
+	"github.com/aerospike/asconfig/schema"
+)
+
+const testLintSchema7 = `{
+	"type": "object",
+	"properties": {
+		"service": {
+			"type": "object",
+			"properties": {
+				"proto-fd-max": {"type": "integer", "default": 15000},
+				"cluster-name": {"type": "string", "default": "", "description": "Replaces the deprecated cluster-id."},
+				"old-setting": {"type": "integer", "default": 0, "description": "Deprecated in 7.0, has no effect."}
+			}
+		},
+		"namespaces": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"replication-factor": {"type": "integer", "default": 2},
+					"storage-engine": {
+						"oneOf": [
+							{"type": "object", "properties": {"type": {"enum": ["memory"]}, "data-size": {"type": "integer"}}},
+							{"type": "object", "properties": {
+								"type": {"enum": ["device"]},
+								"cold-start-empty": {"type": "boolean", "default": false},
+								"disable-odirect": {"type": "boolean", "default": false}
+							}}
+						]
+					}
+				}
+			}
+		}
+	}
+}`
+
+const testLintSchema8 = `{
+	"type": "object",
+	"properties": {
+		"service": {
+			"type": "object",
+			"properties": {
+				"proto-fd-max": {"type": "integer", "default": 15000},
+				"cluster-name": {"type": "string", "default": ""}
+			}
+		},
+		"namespaces": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"replication-factor": {"type": "integer", "default": 2},
+					"storage-engine": {
+						"oneOf": [
+							{"type": "object", "properties": {"type": {"enum": ["memory"]}, "data-size": {"type": "integer"}}},
+							{"type": "object", "properties": {"type": {"enum": ["device"]}, "cold-start-empty": {"type": "boolean", "default": false}}}
+						]
+					}
+				}
+			}
+		}
+	}
+}`
+
+const testLintConf = `
+service {
+	proto-fd-max 20000
+	cluster-name cl1
+	old-setting 5
+}
+namespace test {
+	replication-factor 2
+	storage-engine device {
+		cold-start-empty true
+		disable-odirect true
+	}
+}
+`
+
+func TestLintConfig(t *testing.T) {
+	schemaMap := schema.SchemaMap{"7.0.0": testLintSchema7, "7.1.0": testLintSchema7, "8.0.0": testLintSchema8}
+
+	asconfig, err := asConf.NewASConfigFromBytes(mgmtLibLogger, []byte(testLintConf), asConf.AeroConfig)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	got, err := lintConfig(asconfig, schemaMap, "7.1.0")
+	if err != nil {
+		t.Fatalf("lintConfig() error = %v", err)
+	}
+
+	want := []lintFinding{
+		{Context: "namespaces.test.replication-factor", Check: lintCheckRedundantDefault, Severity: conf.SeverityInfo},
+		{Context: "namespaces.test.storage-engine.cold-start-empty", Check: lintCheckDangerous, Severity: conf.SeverityWarning},
+		{Context: "namespaces.test.storage-engine.disable-odirect", Check: lintCheckDangerous, Severity: conf.SeverityError},
+		{Context: "namespaces.test.storage-engine.disable-odirect", Check: lintCheckRemovedNextMajor, Severity: conf.SeverityWarning},
+		{Context: "service.old-setting", Check: lintCheckDeprecated, Severity: conf.SeverityWarning},
+		{Context: "service.old-setting", Check: lintCheckRemovedNextMajor, Severity: conf.SeverityWarning},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("lintConfig() = %+v, want %d findings", got, len(want))
+	}
+
+	for i, w := range want {
+		if got[i].Context != w.Context || got[i].Check != w.Check || got[i].Severity != w.Severity {
+			t.Errorf("lintConfig()[%d] = %s %s %s, want %s %s %s", i,
+				got[i].Context, got[i].Check, got[i].Severity, w.Context, w.Check, w.Severity)
+		}
+	}
+
+	// there is no newer major version of 8.0.0
+	got, err = lintConfig(asconfig, schemaMap, "8.0.0")
+	if err != nil {
+		t.Fatalf("lintConfig() error = %v", err)
+	}
+
+	for _, f := range got {
+		if f.Check == lintCheckRemovedNextMajor {
+			t.Errorf("lintConfig() for latest major = %+v, want no %s findings", f, lintCheckRemovedNextMajor)
+		}
+	}
+
+	if _, err := lintConfig(asconfig, schemaMap, "6.0.0"); !errors.Is(err, errUnsupportedAerospikeVersion) {
+		t.Errorf("lintConfig() error = %v, want %v", err, errUnsupportedAerospikeVersion)
+	}
+}
+
+func TestNextMajorVersion(t *testing.T) {
+	versions := []string{"6.4.0", "7.0.0", "7.2.0", "8.0.0", "8.1.0"}
+
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{version: "6.4.0", want: "7.0.0"},
+		{version: "7.2.0", want: "8.0.0"},
+		{version: "7.0.0.5", want: "8.0.0"},
+		{version: "8.1.0", want: ""},
+	}
+
+	for _, tt := range tests {
+		got, err := nextMajorVersion(versions, tt.version)
+		if err != nil {
+			t.Fatalf("nextMajorVersion(%s) error = %v", tt.version, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("nextMajorVersion(%s) = %s, want %s", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestLintFails(t *testing.T) {
+	findings := []lintFinding{{Severity: conf.SeverityInfo}, {Severity: conf.SeverityWarning}}
+
+	tests := []struct {
+		failOn string
+		want   bool
+	}{
+		{failOn: "error", want: false},
+		{failOn: "warning", want: true},
+		{failOn: "info", want: true},
+		{failOn: failOnNone, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := lintFails(findings, tt.failOn); got != tt.want {
+			t.Errorf("lintFails(%s) = %v, want %v", tt.failOn, got, tt.want)
+		}
+	}
+}
+
+func TestRenderLintReport(t *testing.T) {
+	report := lintReport{
+		Source:  "aerospike.conf",
+		Version: "7.0.0",
+		Findings: []lintFinding{{
+			Context:     "service.disable-odirect",
+			Check:       lintCheckDangerous,
+			Severity:    conf.SeverityError,
+			Description: "disable-odirect is enabled",
+			Line:        3,
+			Column:      2,
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := renderLintReport(&buf, outputFormatText, report); err != nil {
+		t.Fatalf("renderLintReport() error = %v", err)
+	}
+
+	want := "error: service.disable-odirect (line 3, column 2): disable-odirect is enabled [dangerous]\n"
+	if buf.String() != want {
+		t.Errorf("renderLintReport() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+
+	if err := renderLintReport(&buf, outputFormatJSON, report); err != nil {
+		t.Fatalf("renderLintReport() error = %v", err)
+	}
+
+	var decoded lintReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("renderLintReport() produced invalid json: %v", err)
+	}
+
+	if len(decoded.Findings) != 1 || decoded.Findings[0].Check != lintCheckDangerous {
+		t.Errorf("renderLintReport() = %s, want the dangerous finding", buf.String())
+	}
+}
+
+func TestRunELint(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	src := filepath.Join(t.TempDir(), "aerospike.conf")
+	if err := os.WriteFile(src, []byte(strings.ReplaceAll(testLintConf, "old-setting 5\n", "")), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		flags       []string
+		arguments   []string
+		expectError bool
+	}{
+		{name: "too many args", flags: []string{"-a", "7.0.0"}, arguments: []string{src, src}, expectError: true},
+		{name: "missing version", arguments: []string{src}, expectError: true},
+		{name: "bad fail-on", flags: []string{"-a", "7.0.0", "--fail-on", "bad"}, arguments: []string{src}, expectError: true},
+		{name: "bad output format", flags: []string{"-a", "7.0.0", "--output-format", "sarif"}, arguments: []string{src}, expectError: true},
+		{name: "unsupported version", flags: []string{"-a", "1.0.0"}, arguments: []string{src}, expectError: true},
+		{name: "missing file", flags: []string{"-a", "7.0.0"}, arguments: []string{"./fake_file.conf"}, expectError: true},
+		{name: "redundant default fails on info", flags: []string{"-a", "7.0.0", "--fail-on", "info"}, arguments: []string{src}, expectError: true},
+		{name: "fail-on none", flags: []string{"-a", "7.0.0", "--fail-on", "none"}, arguments: []string{src}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newLintCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			cmd.ParseFlags(tt.flags)
+			err := cmd.RunE(cmd, tt.arguments)
+			if tt.expectError == (err == nil) {
+				t.Fatalf("expectError: %v does not match err: %v", tt.expectError, err)
+			}
+		})
+	}
+}
@@ -2,10 +2,8 @@ package cmd
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 
-	lib "github.com/aerospike/aerospike-management-lib"
 	"github.com/aerospike/asconfig/schema"
 	"github.com/spf13/cobra"
 )
@@ -43,21 +41,8 @@ func newListVersionsCmd() *cobra.Command {
 				return fmt.Errorf("failed to load schema map: %w", err)
 			}
 
-			var versions []string
-			for version := range schemaMap {
-				versions = append(versions, version)
-			}
-
 			// Sort versions using semantic version comparison
-			sort.Slice(versions, func(i, j int) bool {
-				cmp, compErr := lib.CompareVersions(versions[i], versions[j])
-				if compErr != nil {
-					// Fall back to lexical order if comparison fails
-					logger.Warnf("Falling back to lexical version sort: %v", compErr)
-					return versions[i] < versions[j]
-				}
-				return cmp < 0
-			})
+			versions := schemaVersions(schemaMap)
 
 			// Get output format
 			verbose, _ := cmd.Flags().GetBool("verbose")
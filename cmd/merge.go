@@ -0,0 +1,407 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/spf13/cobra"
+
+	"github.com/aerospike/asconfig/conf"
+)
+
+const (
+	mergeArgs = 3
+
+	// sides of a merge.
+	mergeSideOurs   = "ours"
+	mergeSideTheirs = "theirs"
+)
+
+var (
+	errMergeWrongArgs      = fmt.Errorf("merge requires exactly %d arguments: <base> <ours> <theirs>", mergeArgs)
+	errMergeConflicts      = errors.New("merge has conflicts")
+	errInvalidMergePrefer  = errors.New("invalid --prefer, valid options are: ours, theirs")
+	errMismatchedMergeSide = errors.New("merge inputs must have the same format")
+)
+
+func newMergeCmd() *cobra.Command {
+	res := &cobra.Command{
+		Use:   "merge [flags] <path/to/base> <path/to/ours> <path/to/theirs>",
+		Short: "Three-way merge Aerospike configuration files.",
+		Long: `Merge combines the changes made to a base configuration file in two
+				descendant files, ours and theirs. Changes made on one side only are
+				taken as is. Named sections such as namespaces, sets, xdr dcs and logging
+				sinks are matched by name rather than by position. Settings changed
+				differently on both sides are conflicts, they are reported and the merge
+				is not written unless --prefer chooses the side that wins conflicts.
+				The conflict report is written to stderr in the --output-format.
+				The merged configuration is written in the format of ours unless --to is used.`,
+		Example: `
+				# Merge live changes persisted with generate into an environment override
+				asconfig merge base.conf staging.conf generated.conf -o merged.conf
+				# Resolve conflicts with the values of theirs and write yaml
+				asconfig merge --prefer theirs --to yaml base.conf ours.conf theirs.conf
+				# Report conflicts as json
+				asconfig merge --output-format json base.conf ours.conf theirs.conf`,
+		RunE: runMergeCommand,
+	}
+
+	res.Flags().StringP("output", "o", os.Stdout.Name(), "File path to write output to")
+	res.Flags().
+		StringP("format", "F", "conf", "The format of the source files. Valid options are: yaml, yml, json, toml, hcl, and conf.")
+	res.Flags().
+		String("to", "", "The format of the merged configuration. Valid options are: yaml, yml, json, toml, hcl, and conf. Defaults to the format of ours.")
+	res.Flags().
+		String("prefer", "", "The side whose values resolve conflicts. Valid options are: ours, theirs.")
+	res.Flags().
+		String("output-format", outputFormatText, "The format of the conflict report. Valid options are: text, json, and yaml.")
+
+	res.Version = VERSION
+
+	return res
+}
+
+// mergeConflict is a setting changed differently by ours and theirs. Values are
+// nil on the sides where the setting is not set. Resolution is the side chosen
+// by --prefer.
+type mergeConflict struct {
+	Path       string `json:"path"                 yaml:"path"`
+	Base       any    `json:"base"                 yaml:"base"`
+	Ours       any    `json:"ours"                 yaml:"ours"`
+	Theirs     any    `json:"theirs"               yaml:"theirs"`
+	Resolution string `json:"resolution,omitempty" yaml:"resolution,omitempty"`
+}
+
+// mergeReport is the result of a three-way merge.
+type mergeReport struct {
+	Base      string          `json:"base"      yaml:"base"`
+	Ours      string          `json:"ours"      yaml:"ours"`
+	Theirs    string          `json:"theirs"    yaml:"theirs"`
+	Conflicts []mergeConflict `json:"conflicts" yaml:"conflicts"`
+}
+
+func runMergeCommand(cmd *cobra.Command, args []string) error {
+	logger.Debug("Running merge command")
+
+	if len(args) != mergeArgs {
+		return errMergeWrongArgs
+	}
+
+	reportFmt, err := getOutputFormat(cmd, outputFormatText, outputFormatJSON, outputFormatYAML)
+	if err != nil {
+		return err
+	}
+
+	prefer, err := cmd.Flags().GetString("prefer")
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Processing flag prefer value=%s", prefer)
+
+	if prefer != "" && prefer != mergeSideOurs && prefer != mergeSideTheirs {
+		return fmt.Errorf("%w: %s", errInvalidMergePrefer, prefer)
+	}
+
+	configs := make([]map[string]any, 0, mergeArgs)
+
+	var (
+		srcFormat asConf.Format
+		oursData  []byte
+	)
+
+	for i, path := range args {
+		format, err := getConfFileFormat(path, cmd)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 && format != srcFormat {
+			return fmt.Errorf("%w: detected %s and %s", errMismatchedMergeSide, srcFormat, format)
+		}
+
+		srcFormat = format
+
+		fdata, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if i == 1 {
+			oursData = fdata
+		}
+
+		asconfig, err := conf.NewASConfigFromBytes(mgmtLibLogger, fdata, format)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		config, err := migrationConfigMap(asconfig)
+		if err != nil {
+			return err
+		}
+
+		configs = append(configs, config)
+	}
+
+	outFmt, err := getToFormat(cmd, srcFormat)
+	if err != nil {
+		return err
+	}
+
+	merged, conflicts := mergeConfigs(configs[0], configs[1], configs[2], prefer)
+
+	report := mergeReport{Base: args[0], Ours: args[1], Theirs: args[2], Conflicts: conflicts}
+	if len(conflicts) > 0 || reportFmt != outputFormatText {
+		if err := renderMergeReport(cmd.OutOrStderr(), reportFmt, report); err != nil {
+			return err
+		}
+	}
+
+	if len(conflicts) > 0 && prefer == "" {
+		return errors.Join(errMergeConflicts, ErrSilent)
+	}
+
+	mergedConf, err := asConf.NewMapAsConfig(mgmtLibLogger, merged)
+	if err != nil {
+		return err
+	}
+
+	out, err := conf.NewConfigMarshaller(mergedConf, outFmt).MarshalText()
+	if err != nil {
+		return err
+	}
+
+	out, err = addMetaData(out, outFmt, oursData, nil, map[string]string{metaKeyAsconfigVersion: VERSION})
+	if err != nil {
+		return err
+	}
+
+	return writeConvertedOutput(cmd, args[1], outFmt, out)
+}
+
+// mergeValue is a config value and whether it is set.
+type mergeValue struct {
+	val any
+	set bool
+}
+
+func mapMergeValue(m map[string]any, key string) mergeValue {
+	val, ok := m[key]
+	return mergeValue{val: val, set: ok}
+}
+
+func (v mergeValue) equal(o mergeValue) bool {
+	return v.set == o.set && reflect.DeepEqual(v.val, o.val)
+}
+
+// reportValue returns the value reported in a conflict, nil if it is not set.
+func (v mergeValue) reportValue() any {
+	if !v.set {
+		return nil
+	}
+
+	return v.val
+}
+
+// mergeConfigs merges the changes that ours and theirs made to base. The
+// conflicts are resolved with the prefer side, or with ours if prefer is empty.
+func mergeConfigs(base, ours, theirs map[string]any, prefer string) (map[string]any, []mergeConflict) {
+	conflicts := []mergeConflict{}
+
+	merged := mergeValues(
+		mergeValue{val: base, set: true},
+		mergeValue{val: ours, set: true},
+		mergeValue{val: theirs, set: true},
+		"", prefer, &conflicts,
+	)
+
+	res, _ := merged.val.(map[string]any)
+
+	return res, conflicts
+}
+
+// mergeValues merges the values of the setting or section at context.
+func mergeValues(base, ours, theirs mergeValue, context, prefer string, conflicts *[]mergeConflict) mergeValue {
+	switch {
+	case ours.equal(theirs), base.equal(theirs):
+		return ours
+	case base.equal(ours):
+		return theirs
+	}
+
+	if ours.set && theirs.set {
+		baseMap, _ := base.val.(map[string]any)
+		oursMap, oursIsMap := ours.val.(map[string]any)
+		theirsMap, theirsIsMap := theirs.val.(map[string]any)
+
+		if oursIsMap && theirsIsMap {
+			return mergeValue{val: mergeMaps(baseMap, oursMap, theirsMap, context, prefer, conflicts), set: true}
+		}
+
+		baseList, _ := base.val.([]any)
+		oursList, oursIsList := ours.val.([]any)
+		theirsList, theirsIsList := theirs.val.([]any)
+
+		if oursIsList && theirsIsList && isNamedList(baseList) && isNamedList(oursList) && isNamedList(theirsList) {
+			return mergeValue{val: mergeNamedLists(baseList, oursList, theirsList, context, prefer, conflicts), set: true}
+		}
+	}
+
+	conflict := mergeConflict{
+		Path:       context,
+		Base:       base.reportValue(),
+		Ours:       ours.reportValue(),
+		Theirs:     theirs.reportValue(),
+		Resolution: prefer,
+	}
+
+	*conflicts = append(*conflicts, conflict)
+
+	if prefer == mergeSideTheirs {
+		return theirs
+	}
+
+	return ours
+}
+
+// mergeMaps merges the keys of a config section.
+func mergeMaps(base, ours, theirs map[string]any, context, prefer string, conflicts *[]mergeConflict) map[string]any {
+	res := map[string]any{}
+
+	for _, key := range mergeKeys(base, ours, theirs) {
+		merged := mergeValues(
+			mapMergeValue(base, key),
+			mapMergeValue(ours, key),
+			mapMergeValue(theirs, key),
+			conf.JoinContext(context, key), prefer, conflicts,
+		)
+
+		if merged.set {
+			res[key] = merged.val
+		}
+	}
+
+	return res
+}
+
+// mergeKeys returns the sorted keys of the maps.
+func mergeKeys(maps ...map[string]any) []string {
+	var res []string
+
+	for _, m := range maps {
+		for key := range m {
+			if !slices.Contains(res, key) {
+				res = append(res, key)
+			}
+		}
+	}
+
+	slices.Sort(res)
+
+	return res
+}
+
+// isNamedList reports whether every item of list is a section with a name,
+// such as namespaces or logging sinks.
+func isNamedList(list []any) bool {
+	for _, item := range list {
+		itemMap, ok := item.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		if _, ok := itemMap[keyNameField].(string); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// namedItems indexes the items of a named list by name.
+func namedItems(list []any) map[string]any {
+	res := make(map[string]any, len(list))
+
+	for _, item := range list {
+		name, _ := item.(map[string]any)[keyNameField].(string)
+		res[name] = item
+	}
+
+	return res
+}
+
+// mergeNamedLists merges the items of named lists by name. The order of the
+// merged items is not kept, the management lib reorders the items of named
+// lists when the merged config is written.
+func mergeNamedLists(base, ours, theirs []any, context, prefer string, conflicts *[]mergeConflict) []any {
+	baseItems, oursItems, theirsItems := namedItems(base), namedItems(ours), namedItems(theirs)
+
+	var names []string
+
+	for _, list := range [][]any{ours, theirs, base} {
+		for _, item := range list {
+			name, _ := item.(map[string]any)[keyNameField].(string)
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	res := make([]any, 0, len(names))
+
+	for _, name := range names {
+		merged := mergeValues(
+			mapMergeValue(baseItems, name),
+			mapMergeValue(oursItems, name),
+			mapMergeValue(theirsItems, name),
+			conf.JoinContext(context, name), prefer, conflicts,
+		)
+
+		if merged.set {
+			res = append(res, merged.val)
+		}
+	}
+
+	return res
+}
+
+// renderMergeReport writes the merge conflicts to w in outFmt.
+func renderMergeReport(w io.Writer, outFmt string, report mergeReport) error {
+	if outFmt != outputFormatText {
+		return renderStructured(w, outFmt, report)
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%d conflicts merging %s and %s into %s:\n",
+		len(report.Conflicts), report.Ours, report.Theirs, report.Base)
+
+	for _, conflict := range report.Conflicts {
+		fmt.Fprintf(&sb, "%s:\n\tbase: %s\n\tours: %s\n\ttheirs: %s\n", conflict.Path,
+			mergeValueString(conflict.Base), mergeValueString(conflict.Ours), mergeValueString(conflict.Theirs))
+
+		if conflict.Resolution != "" {
+			fmt.Fprintf(&sb, "\tresolved with: %s\n", conflict.Resolution)
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// mergeValueString formats a conflicting value for the text report.
+func mergeValueString(val any) string {
+	if val == nil {
+		return "<not set>"
+	}
+
+	return fmt.Sprint(val)
+}
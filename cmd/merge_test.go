@@ -0,0 +1,195 @@
+//go:build unit
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeConfigs(t *testing.T) {
+	base := map[string]any{
+		"service": map[string]any{"proto-fd-max": int64(15000), "cluster-name": "cl1"},
+		"namespaces": []any{
+			map[string]any{"name": "test", "replication-factor": int64(2)},
+			map[string]any{"name": "bar", "replication-factor": int64(2)},
+		},
+	}
+
+	// ours reorders the namespaces, raises proto-fd-max and removes cluster-name
+	ours := map[string]any{
+		"service": map[string]any{"proto-fd-max": int64(20000)},
+		"namespaces": []any{
+			map[string]any{"name": "bar", "replication-factor": int64(2)},
+			map[string]any{"name": "test", "replication-factor": int64(3)},
+		},
+	}
+
+	// theirs changes bar, adds a namespace and changes test's replication-factor differently
+	theirs := map[string]any{
+		"service": map[string]any{"proto-fd-max": int64(15000), "cluster-name": "cl1"},
+		"namespaces": []any{
+			map[string]any{"name": "test", "replication-factor": int64(1)},
+			map[string]any{"name": "bar", "replication-factor": int64(2), "default-ttl": int64(60)},
+			map[string]any{"name": "new", "replication-factor": int64(2)},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		prefer string
+		wantRF int64
+	}{
+		{name: "unresolved", wantRF: 3},
+		{name: "prefer ours", prefer: mergeSideOurs, wantRF: 3},
+		{name: "prefer theirs", prefer: mergeSideTheirs, wantRF: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts := mergeConfigs(base, ours, theirs, tt.prefer)
+
+			wantConflicts := []mergeConflict{{
+				Path:       "namespaces.test.replication-factor",
+				Base:       int64(2),
+				Ours:       int64(3),
+				Theirs:     int64(1),
+				Resolution: tt.prefer,
+			}}
+			if !reflect.DeepEqual(conflicts, wantConflicts) {
+				t.Errorf("mergeConfigs() conflicts = %+v, want %+v", conflicts, wantConflicts)
+			}
+
+			want := map[string]any{
+				"service": map[string]any{"proto-fd-max": int64(20000)},
+				"namespaces": []any{
+					map[string]any{"name": "bar", "replication-factor": int64(2), "default-ttl": int64(60)},
+					map[string]any{"name": "test", "replication-factor": tt.wantRF},
+					map[string]any{"name": "new", "replication-factor": int64(2)},
+				},
+			}
+			if !reflect.DeepEqual(merged, want) {
+				t.Errorf("mergeConfigs() = %v, want %v", merged, want)
+			}
+		})
+	}
+}
+
+func TestMergeConfigsRemovedSection(t *testing.T) {
+	base := map[string]any{"namespaces": []any{map[string]any{"name": "test", "default-ttl": int64(0)}}}
+	ours := map[string]any{"namespaces": []any{}}
+	theirs := map[string]any{"namespaces": []any{map[string]any{"name": "test", "default-ttl": int64(60)}}}
+
+	_, conflicts := mergeConfigs(base, ours, theirs, "")
+	if len(conflicts) != 1 || conflicts[0].Path != "namespaces.test" || conflicts[0].Ours != nil {
+		t.Errorf("mergeConfigs() conflicts = %+v, want the removed and changed namespaces.test", conflicts)
+	}
+
+	var buf bytes.Buffer
+	if err := renderMergeReport(&buf, outputFormatText, mergeReport{Base: "b", Ours: "o", Theirs: "t", Conflicts: conflicts}); err != nil {
+		t.Fatalf("renderMergeReport() error = %v", err)
+	}
+
+	want := "1 conflicts merging o and t into b:\nnamespaces.test:\n\tbase: map[default-ttl:0 name:test]\n" +
+		"\tours: <not set>\n\ttheirs: map[default-ttl:60 name:test]\n"
+	if buf.String() != want {
+		t.Errorf("renderMergeReport() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRunMergeCommand(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	dir := t.TempDir()
+	paths := map[string]string{
+		"base.conf":   "service {\n\tproto-fd-max 15000\n}\nnamespace test {\n\treplication-factor 2\n\tstorage-engine memory\n}\n",
+		"ours.conf":   "service {\n\tproto-fd-max 20000\n}\nnamespace test {\n\treplication-factor 3\n\tstorage-engine memory\n}\n",
+		"theirs.conf": "service {\n\tproto-fd-max 15000\n}\nnamespace test {\n\treplication-factor 1\n\tstorage-engine memory\n}\n",
+		"clean.conf":  "service {\n\tproto-fd-max 15000\n}\nnamespace test {\n\treplication-factor 2\n\tdefault-ttl 60\n\tstorage-engine memory\n}\n",
+	}
+
+	for name, data := range paths {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+	}
+
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name       string
+		flags      []string
+		arguments  []string
+		wantErr    error
+		wantOutput []string
+	}{
+		{
+			name:       "clean merge",
+			arguments:  []string{path("base.conf"), path("ours.conf"), path("clean.conf")},
+			wantOutput: []string{"proto-fd-max 20000", "replication-factor 3", "default-ttl 60"},
+		},
+		{
+			name:      "conflict",
+			arguments: []string{path("base.conf"), path("ours.conf"), path("theirs.conf")},
+			wantErr:   errMergeConflicts,
+		},
+		{
+			name:       "prefer theirs as yaml",
+			flags:      []string{"--prefer", "theirs", "--to", "yaml"},
+			arguments:  []string{path("base.conf"), path("ours.conf"), path("theirs.conf")},
+			wantOutput: []string{"proto-fd-max: 20000", "replication-factor: 1"},
+		},
+		{name: "wrong args", arguments: []string{path("base.conf"), path("ours.conf")}, wantErr: errMergeWrongArgs},
+		{
+			name:      "invalid prefer",
+			flags:     []string{"--prefer", "both"},
+			arguments: []string{path("base.conf"), path("ours.conf"), path("theirs.conf")},
+			wantErr:   errInvalidMergePrefer,
+		},
+		{
+			name:      "invalid to",
+			flags:     []string{"--to", "ini"},
+			arguments: []string{path("base.conf"), path("ours.conf"), path("theirs.conf")},
+			wantErr:   errInvalidToFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outPath := filepath.Join(t.TempDir(), "merged")
+
+			cmd := newMergeCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			cmd.ParseFlags(append(tt.flags, "--output", outPath))
+			err := cmd.RunE(cmd, tt.arguments)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunE() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			out, err := os.ReadFile(outPath)
+			if err != nil {
+				t.Fatalf("Failed to read merged config: %v", err)
+			}
+
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(strings.Join(strings.Fields(string(out)), " "), want) {
+					t.Errorf("RunE() output = %s, want it to contain %q", out, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,789 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	lib "github.com/aerospike/aerospike-management-lib"
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/spf13/cobra"
+
+	"github.com/aerospike/asconfig/conf"
+	"github.com/aerospike/asconfig/schema"
+)
+
+const (
+	migrateArgMax = 1
+
+	// migration actions.
+	migrationRename  = "rename"
+	migrationDrop    = "drop"
+	migrationConvert = "convert"
+	migrationAdd     = "add"
+	migrationManual  = "manual"
+
+	requiredField = "required"
+	defaultField  = "default"
+	enumField     = "enum"
+)
+
+var (
+	errMigrateTooManyArguments = fmt.Errorf("migrate accepts a maximum of %d argument", migrateArgMax)
+	errMigrateMissingTo        = errors.New("missing required flag '--to'")
+	errMigrateMissingFrom      = errors.New("missing flag '--from' and the source has no aerospike-server-version metadata")
+	errMigrateNotUpgrade       = errors.New("migrate only supports upgrading to a newer server version")
+)
+
+func newMigrateCmd() *cobra.Command {
+	res := &cobra.Command{
+		Use:   "migrate [flags] [path/to/config_file]",
+		Short: "Migrate an Aerospike configuration file to a newer server version.",
+		Long: `Migrate rewrites a configuration file for a newer Aerospike server version.
+				Moved parameters are renamed, removed parameters are dropped,
+				settings are converted to new models, Ex: namespace memory-size
+				becomes the storage-engine memory data-size in 7.0, and newly required
+				fields with a default value are added. A report of every transformation
+				is written to stderr. Transformations that need a decision are reported
+				as manual. The result is validated against the --to version and is only
+				written if it is valid, unless --force is used.
+				The --from version defaults to the version in the source metadata.
+				If a file path is not provided, migrate reads from stdin.`,
+		Example: `
+				# Migrate a 6.4 config to 7.2 and write it to a new file
+				asconfig migrate --from 6.4.0 --to 7.2.0 aerospike.conf -o aerospike-7.conf
+				# Migrate a yaml config converted by asconfig, the source version is in its metadata
+				asconfig migrate --to 8.0.0 aerospike.yaml`,
+		RunE: runMigrateCommand,
+	}
+
+	res.Flags().String("from", "", "The Aerospike server version the source file is configured for. Ex: 6.4.0.")
+	res.Flags().String("to", "", "The Aerospike server version to migrate the configuration to. Ex: 7.2.0.")
+	res.Flags().BoolP("force", "f", false, "Write the migrated configuration even if it fails validation.")
+	res.Flags().StringP("output", "o", os.Stdout.Name(), "File path to write output to")
+	res.Flags().
+		StringP("format", "F", "conf", "The format of the source file. Valid options are: yaml, yml, json, toml, hcl, and conf.")
+
+	res.Version = VERSION
+
+	return res
+}
+
+// migrationChange is a single transformation made by migrate.
+type migrationChange struct {
+	Action      string `json:"action"`
+	Context     string `json:"context"`
+	Target      string `json:"target,omitempty"`
+	Value       any    `json:"value,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// String describes the change on a single line.
+func (c migrationChange) String() string {
+	res := c.Action + ": " + c.Context
+
+	if c.Target != "" {
+		res += " -> " + c.Target
+	}
+
+	if c.Value != nil && c.Action == migrationAdd {
+		res += fmt.Sprintf(" = %v", c.Value)
+	}
+
+	if c.Description != "" {
+		res += " (" + c.Description + ")"
+	}
+
+	return res
+}
+
+func runMigrateCommand(cmd *cobra.Command, args []string) error {
+	logger.Debug("Running migrate command")
+
+	if len(args) > migrateArgMax {
+		return errMigrateTooManyArguments
+	}
+
+	// read stdin by default
+	srcPath := os.Stdin.Name()
+	if len(args) > 0 {
+		srcPath = args[0]
+	}
+
+	srcFormat, err := getConfFileFormat(srcPath, cmd)
+	if err != nil {
+		return err
+	}
+
+	fdata, err := os.ReadFile(srcPath)
+	if err != nil {
+		return err
+	}
+
+	from, to, err := getMigrateVersions(cmd, fdata)
+	if err != nil {
+		return err
+	}
+
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+
+	asconfig, err := conf.NewASConfigFromBytes(mgmtLibLogger, fdata, srcFormat)
+	if err != nil {
+		return err
+	}
+
+	schemaMap, err := schema.NewSchemaMap()
+	if err != nil {
+		return fmt.Errorf("failed to load schema map: %w", err)
+	}
+
+	migrated, changes, err := migrateConfig(asconfig, schemaMap, from, to)
+	if err != nil {
+		return err
+	}
+
+	if err := renderMigrationReport(cmd.OutOrStderr(), from, to, changes); err != nil {
+		return err
+	}
+
+	out, err := conf.NewConfigMarshaller(migrated, srcFormat).MarshalText()
+	if err != nil {
+		return err
+	}
+
+	out, err = addMetaData(
+		out,
+		srcFormat,
+		fdata,
+		nil,
+		map[string]string{
+			metaKeyAerospikeVersion: to,
+			metaKeyAsconfigVersion:  VERSION,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	verrs, errValidate := newSourceValidator(
+		migrated, out, srcFormat, to, conf.RuleOptions{},
+	).Validate()
+	if verrs != nil && len(verrs.Errors) > 0 {
+		fmt.Fprintf(cmd.OutOrStderr(), "\nValidation against %s:\n%s", to, verrs.Error())
+	}
+
+	if errValidate != nil && !force {
+		return errors.Join(errValidate, ErrSilent)
+	}
+
+	return writeConvertedOutput(cmd, srcPath, srcFormat, out)
+}
+
+// getMigrateVersions returns the --from and --to versions. The source
+// version defaults to the version in the metadata of src.
+func getMigrateVersions(cmd *cobra.Command, src []byte) (string, string, error) {
+	from, err := cmd.Flags().GetString("from")
+	if err != nil {
+		return "", "", err
+	}
+
+	to, err := cmd.Flags().GetString("to")
+	if err != nil {
+		return "", "", err
+	}
+
+	logger.Debugf("Processing flag from value=%s to value=%s", from, to)
+
+	if to == "" {
+		return "", "", errMigrateMissingTo
+	}
+
+	if from == "" {
+		from, err = getMetaDataItemOptional(src, metaKeyAerospikeVersion)
+		if err != nil || from == "" {
+			return "", "", errors.Join(errMigrateMissingFrom, err)
+		}
+	}
+
+	cmp, err := lib.CompareVersions(from, to)
+	if err != nil {
+		return "", "", errors.Join(errInvalidAerospikeVersion, err)
+	}
+
+	if cmp >= 0 {
+		return "", "", fmt.Errorf("%w: %s to %s", errMigrateNotUpgrade, from, to)
+	}
+
+	return from, to, nil
+}
+
+// migrateConfig migrates asconfig from the from server version to the to version.
+// The migrated configuration is returned with every change that was made.
+func migrateConfig(
+	asconfig conf.ConfHandler,
+	schemaMap schema.SchemaMap,
+	from, to string,
+) (*asConf.AsConfig, []migrationChange, error) {
+	schemaFrom, err := loadSchema(schemaMap, from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schemaTo, err := loadSchema(schemaMap, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config, err := migrationConfigMap(asconfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes := []migrationChange{}
+
+	// version specific steps run first, they move settings
+	// that would otherwise be dropped as removed
+	for _, step := range versionMigrations {
+		applies, errApplies := migrationApplies(step.version, from, to)
+		if errApplies != nil {
+			return nil, nil, errApplies
+		}
+
+		if applies {
+			changes = append(changes, step.apply(config, schemaTo)...)
+		}
+	}
+
+	dropped, err := dropRemovedSettings(config, schemaFrom, schemaTo, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes = append(changes, dropped...)
+	changes = append(changes, addRequiredDefaults(config, schemaTo, "")...)
+
+	pruneEmptySections(config)
+
+	migrated, err := asConf.NewMapAsConfig(mgmtLibLogger, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return migrated, changes, nil
+}
+
+// migrationApplies reports whether a step introduced in version is crossed by an upgrade from -> to.
+func migrationApplies(version, from, to string) (bool, error) {
+	cmpFrom, err := lib.CompareVersions(from, version)
+	if err != nil {
+		return false, err
+	}
+
+	cmpTo, err := lib.CompareVersions(to, version)
+	if err != nil {
+		return false, err
+	}
+
+	return cmpFrom < 0 && cmpTo >= 0, nil
+}
+
+// migrationConfigMap returns the configuration as generic json types.
+// Whole numbers are kept as integers so they are written without an exponent.
+func migrationConfigMap(asconfig conf.ConfHandler) (map[string]any, error) {
+	data, err := json.Marshal(asconfig.ToMap())
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	res := map[string]any{}
+	if err := dec.Decode(&res); err != nil {
+		return nil, err
+	}
+
+	return normalizeJSONNumbers(res).(map[string]any), nil //nolint:errcheck // maps stay maps
+}
+
+// normalizeJSONNumbers converts json numbers in v to int64 or float64.
+func normalizeJSONNumbers(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			val[k] = normalizeJSONNumbers(child)
+		}
+	case []any:
+		for i, child := range val {
+			val[i] = normalizeJSONNumbers(child)
+		}
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+
+		f, _ := val.Float64()
+
+		return f
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < math.MaxInt64 {
+			return int64(val)
+		}
+	}
+
+	return v
+}
+
+// versionMigration is a migration step that applies when an upgrade crosses version.
+type versionMigration struct {
+	version string
+	apply   func(config map[string]any, schemaTo map[string]any) []migrationChange
+	// renames are the namespace settings that apply moves
+	renames []namespaceRename
+}
+
+// versionMigrations are the version specific migration steps in version order.
+var versionMigrations = []versionMigration{
+	{version: "7.0.0", apply: migrateNamespaces70, renames: namespaceRenames70},
+}
+
+// namespaceRename moves a namespace setting, paths are relative to the namespace.
+type namespaceRename struct {
+	from string
+	to   string
+}
+
+// namespaceRenames70 are the namespace settings that moved in server 7.0.
+var namespaceRenames70 = []namespaceRename{
+	{from: "high-water-disk-pct", to: "storage-engine.evict-used-pct"},
+	{from: "high-water-memory-pct", to: "evict-sys-memory-pct"},
+	{from: "stop-writes-pct", to: "stop-writes-sys-memory-pct"},
+	{from: "storage-engine.max-used-pct", to: "storage-engine.stop-writes-used-pct"},
+	{from: "storage-engine.min-avail-pct", to: "storage-engine.stop-writes-avail-pct"},
+}
+
+// migrateNamespaces70 moves namespaces to the unified storage model of server 7.0.
+// The namespace memory-size is replaced by the storage-engine memory data-size,
+// and data-in-memory is removed.
+func migrateNamespaces70(config map[string]any, schemaTo map[string]any) []migrationChange {
+	var res []migrationChange
+
+	nsProp, hasNamespaces := schemaProperties(schemaTo, "")["namespaces"]
+
+	var nsSchema schemaProperty
+	if hasNamespaces {
+		nsSchema, _ = schemaItems(nsProp.node, nsProp.pointer)
+	}
+
+	list, _ := config["namespaces"].([]any)
+
+	for i, item := range list {
+		ns, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		name, ok := ns[keyNameField].(string)
+		if !ok {
+			name = strconv.Itoa(i)
+		}
+
+		context := "namespaces." + name
+
+		for _, rename := range namespaceRenames70 {
+			val, ok := lookupPath(ns, rename.from)
+			if !ok {
+				continue
+			}
+
+			deletePath(ns, rename.from)
+
+			// only move settings that the target version knows about
+			if nsSchema.node != nil && !schemaHasPath(nsSchema.node, rename.to) {
+				res = append(res, migrationChange{
+					Action:      migrationDrop,
+					Context:     context + "." + rename.from,
+					Description: "replaced by " + rename.to + " which is not supported by the target version",
+				})
+
+				continue
+			}
+
+			setPath(ns, rename.to, val)
+			res = append(res, migrationChange{
+				Action:  migrationRename,
+				Context: context + "." + rename.from,
+				Target:  context + "." + rename.to,
+			})
+		}
+
+		res = append(res, migrateMemoryModel70(ns, context)...)
+	}
+
+	return res
+}
+
+// migrateMemoryModel70 converts the memory settings of the namespace ns.
+func migrateMemoryModel70(ns map[string]any, context string) []migrationChange {
+	var res []migrationChange
+
+	engine, _ := ns["storage-engine"].(map[string]any)
+	engineType, _ := engine[keyTypeField].(string)
+
+	if memorySize, ok := ns["memory-size"]; ok {
+		delete(ns, "memory-size")
+
+		if engineType == "memory" {
+			engine["data-size"] = memorySize
+			res = append(res, migrationChange{
+				Action:      migrationConvert,
+				Context:     context + ".memory-size",
+				Target:      context + ".storage-engine.data-size",
+				Description: "in-memory namespaces are sized by the storage-engine data-size",
+			})
+		} else {
+			res = append(res, migrationChange{
+				Action:      migrationDrop,
+				Context:     context + ".memory-size",
+				Description: "primary and secondary indexes are no longer limited by memory-size, see indexes-memory-budget",
+			})
+		}
+	}
+
+	if dataInMemory, ok := engine["data-in-memory"]; ok {
+		delete(engine, "data-in-memory")
+
+		change := migrationChange{
+			Action:      migrationDrop,
+			Context:     context + ".storage-engine.data-in-memory",
+			Description: "data-in-memory is removed",
+		}
+
+		if enabled, _ := dataInMemory.(bool); enabled {
+			// converting to storage-engine memory backed by the devices changes
+			// how the namespace is sized so it is left to the user
+			change.Action = migrationManual
+			change.Description = "data-in-memory is removed, use storage-engine memory with " +
+				"the namespace's devices or files as persistence"
+		}
+
+		res = append(res, change)
+	}
+
+	return res
+}
+
+// dropRemovedSettings removes the settings of config that are in schemaFrom
+// but removed in schemaTo, as reported by compareSchemas.
+func dropRemovedSettings(
+	config map[string]any,
+	schemaFrom, schemaTo map[string]any,
+	from, to string,
+) ([]migrationChange, error) {
+	summary, err := compareSchemas(schemaFrom, schemaTo, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare schemas: %w", err)
+	}
+
+	var removed []string
+
+	for _, section := range summary.Sections {
+		for _, change := range section.Removals {
+			removed = append(removed, change.Path)
+		}
+	}
+
+	var res []migrationChange
+
+	walkConfigSchema(config, schemaProperty{node: schemaFrom}, "",
+		func(context, _ string, _ any, prop schemaProperty) {
+			if isRemovedPointer(removed, prop.pointer) {
+				res = append(res, migrationChange{
+					Action:      migrationDrop,
+					Context:     context,
+					Description: "removed in " + to,
+				})
+			}
+		},
+	)
+
+	for _, change := range res {
+		deleteContext(config, change.Context)
+	}
+
+	return res, nil
+}
+
+// addRequiredDefaults adds the required settings of node that are missing from
+// config. Settings without a default value are reported as manual changes.
+func addRequiredDefaults(config map[string]any, node map[string]any, context string) []migrationChange {
+	var res []migrationChange
+
+	required := []any{}
+	nodes := append([]map[string]any{node}, matchingAlternatives(node, config)...)
+
+	for _, n := range nodes {
+		req, _ := n[requiredField].([]any)
+		required = append(required, req...)
+	}
+
+	props := schemaProperties(node, "")
+
+	for _, r := range required {
+		key, ok := r.(string)
+		if !ok {
+			continue
+		}
+
+		if _, ok := config[key]; ok {
+			continue
+		}
+
+		childCtx := conf.JoinContext(context, key)
+
+		def, ok := props[key].node[defaultField]
+		if !ok {
+			res = append(res, migrationChange{
+				Action:      migrationManual,
+				Context:     childCtx,
+				Description: "required and has no default value",
+			})
+
+			continue
+		}
+
+		def = normalizeJSONNumbers(def)
+		config[key] = def
+		res = append(res, migrationChange{
+			Action:  migrationAdd,
+			Context: childCtx,
+			Value:   def,
+		})
+	}
+
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child, ok := props[key]
+		if !ok {
+			continue
+		}
+
+		childCtx := conf.JoinContext(context, key)
+
+		switch val := config[key].(type) {
+		case map[string]any:
+			res = append(res, addRequiredDefaults(val, child.node, childCtx)...)
+		case []any:
+			items, ok := schemaItems(child.node, "")
+			if !ok || !isSectionList(val) {
+				continue
+			}
+
+			for i, item := range val {
+				itemMap := item.(map[string]any) //nolint:errcheck // checked by isSectionList
+
+				name, ok := itemMap[keyNameField].(string)
+				if !ok {
+					name = strconv.Itoa(i)
+				}
+
+				res = append(res, addRequiredDefaults(itemMap, items.node, childCtx+"."+name)...)
+			}
+		}
+	}
+
+	return res
+}
+
+// matchingAlternatives returns the oneOf, anyOf and allOf alternatives of node that can
+// describe config. Alternatives that restrict the type field must allow config's type.
+func matchingAlternatives(node map[string]any, config map[string]any) []map[string]any {
+	var res []map[string]any
+
+	for _, keyword := range schemaCombinators {
+		alternatives, _ := node[keyword].([]any)
+
+		for _, alt := range alternatives {
+			altMap, ok := alt.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			if altType, _ := altMap[keyTypeField].(string); altType != "" && altType != "object" {
+				continue
+			}
+
+			typeProp, ok := schemaProperties(altMap, "")[keyTypeField]
+			if ok {
+				enum, _ := typeProp.node[enumField].([]any)
+				if len(enum) > 0 && !containsValue(enum, config[keyTypeField]) {
+					continue
+				}
+			}
+
+			res = append(res, altMap)
+		}
+	}
+
+	return res
+}
+
+// containsValue reports whether list contains v.
+func containsValue(list []any, v any) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// schemaHasPath reports whether the dotted path of properties is defined by node.
+func schemaHasPath(node map[string]any, path string) bool {
+	for _, key := range strings.Split(path, ".") {
+		prop, ok := schemaProperties(node, "")[key]
+		if !ok {
+			return false
+		}
+
+		node = prop.node
+	}
+
+	return true
+}
+
+// lookupPath returns the value at a dotted path of nested sections.
+func lookupPath(m map[string]any, path string) (any, bool) {
+	keys := strings.Split(path, ".")
+
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := m[key].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		m = child
+	}
+
+	val, ok := m[keys[len(keys)-1]]
+
+	return val, ok
+}
+
+// setPath sets the value at a dotted path, creating sections as needed.
+func setPath(m map[string]any, path string, val any) {
+	keys := strings.Split(path, ".")
+
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := m[key].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			m[key] = child
+		}
+
+		m = child
+	}
+
+	m[keys[len(keys)-1]] = val
+}
+
+// deletePath deletes the value at a dotted path of nested sections.
+func deletePath(m map[string]any, path string) {
+	keys := strings.Split(path, ".")
+
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := m[key].(map[string]any)
+		if !ok {
+			return
+		}
+
+		m = child
+	}
+
+	delete(m, keys[len(keys)-1])
+}
+
+// deleteContext deletes the setting at a name resolved context. Ex: namespaces.test.memory-size.
+func deleteContext(config map[string]any, context string) {
+	keys := strings.Split(context, ".")
+
+	var node any = config
+
+	for _, key := range keys[:len(keys)-1] {
+		switch val := node.(type) {
+		case map[string]any:
+			node = val[key]
+		case []any:
+			node = findListItem(val, key)
+		default:
+			return
+		}
+	}
+
+	if m, ok := node.(map[string]any); ok {
+		delete(m, keys[len(keys)-1])
+	}
+}
+
+// findListItem returns the section in list with the given name, or at the index name.
+func findListItem(list []any, name string) any {
+	if i := listItemIndex(list, name); i >= 0 {
+		return list[i]
+	}
+
+	return nil
+}
+
+// pruneEmptySections removes null values and sections left empty by the migration.
+func pruneEmptySections(m map[string]any) {
+	for key, val := range m {
+		switch v := val.(type) {
+		case nil:
+			delete(m, key)
+		case map[string]any:
+			pruneEmptySections(v)
+
+			if len(v) == 0 {
+				delete(m, key)
+			}
+		case []any:
+			for _, item := range v {
+				if itemMap, ok := item.(map[string]any); ok {
+					pruneEmptySections(itemMap)
+				}
+			}
+		}
+	}
+}
+
+// renderMigrationReport writes every migration change to w.
+func renderMigrationReport(w io.Writer, from, to string, changes []migrationChange) error {
+	if _, err := fmt.Fprintf(w, "Migrated from %s to %s with %d changes\n", from, to, len(changes)); err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if _, err := fmt.Fprintf(w, "\t- %s\n", change); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+//go:build unit
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+
+	"github.com/aerospike/asconfig/schema"
+)
+
+const testMigrateSchema64 = `{
+	"type": "object",
+	"properties": {
+		"service": {
+			"type": "object",
+			"properties": {
+				"proto-fd-max": {"type": "integer", "default": 15000},
+				"old-setting": {"type": "integer", "default": 0}
+			}
+		},
+		"namespaces": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"memory-size": {"type": "integer"},
+					"high-water-disk-pct": {"type": "integer"},
+					"storage-engine": {
+						"oneOf": [
+							{"type": "object", "properties": {"type": {"enum": ["memory"]}}},
+							{"type": "object", "properties": {
+								"type": {"enum": ["device"]},
+								"files": {"type": "array"},
+								"data-in-memory": {"type": "boolean"},
+								"max-used-pct": {"type": "integer"}
+							}}
+						]
+					}
+				}
+			}
+		}
+	}
+}`
+
+const testMigrateSchema70 = `{
+	"type": "object",
+	"properties": {
+		"service": {
+			"type": "object",
+			"required": ["proto-fd-max"],
+			"properties": {
+				"proto-fd-max": {"type": "integer", "default": 15000}
+			}
+		},
+		"namespaces": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"required": ["name", "replication-factor"],
+				"properties": {
+					"name": {"type": "string"},
+					"replication-factor": {"type": "integer", "default": 2},
+					"storage-engine": {
+						"oneOf": [
+							{"type": "object", "required": ["data-size"], "properties": {
+								"type": {"enum": ["memory"]},
+								"data-size": {"type": "integer"}
+							}},
+							{"type": "object", "required": ["flush-size"], "properties": {
+								"type": {"enum": ["device"]},
+								"files": {"type": "array"},
+								"evict-used-pct": {"type": "integer"},
+								"flush-size": {"type": "integer"}
+							}}
+						]
+					}
+				}
+			}
+		}
+	}
+}`
+
+const testMigrateConf = `
+service {
+	old-setting 1
+}
+namespace test {
+	memory-size 4G
+	storage-engine memory
+}
+namespace bar {
+	memory-size 4G
+	high-water-disk-pct 50
+	storage-engine device {
+		file /opt/bar.dat
+		data-in-memory true
+		max-used-pct 70
+	}
+}
+`
+
+func TestMigrateConfig(t *testing.T) {
+	schemaMap := schema.SchemaMap{"6.4.0": testMigrateSchema64, "7.0.0": testMigrateSchema70}
+
+	asconfig, err := asConf.NewASConfigFromBytes(mgmtLibLogger, []byte(testMigrateConf), asConf.AeroConfig)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	migrated, changes, err := migrateConfig(asconfig, schemaMap, "6.4.0", "7.0.0")
+	if err != nil {
+		t.Fatalf("migrateConfig() error = %v", err)
+	}
+
+	want := []migrationChange{
+		{Action: migrationConvert, Context: "namespaces.test.memory-size", Target: "namespaces.test.storage-engine.data-size"},
+		{Action: migrationRename, Context: "namespaces.bar.high-water-disk-pct", Target: "namespaces.bar.storage-engine.evict-used-pct"},
+		{Action: migrationDrop, Context: "namespaces.bar.storage-engine.max-used-pct"},
+		{Action: migrationDrop, Context: "namespaces.bar.memory-size"},
+		{Action: migrationManual, Context: "namespaces.bar.storage-engine.data-in-memory"},
+		{Action: migrationDrop, Context: "service.old-setting"},
+		{Action: migrationAdd, Context: "namespaces.test.replication-factor", Value: int64(2)},
+		{Action: migrationAdd, Context: "namespaces.bar.replication-factor", Value: int64(2)},
+		{Action: migrationManual, Context: "namespaces.bar.storage-engine.flush-size"},
+		{Action: migrationAdd, Context: "service.proto-fd-max", Value: int64(15000)},
+	}
+
+	if len(changes) != len(want) {
+		t.Fatalf("migrateConfig() changes = %+v, want %d changes", changes, len(want))
+	}
+
+	for i, w := range want {
+		got := changes[i]
+		if got.Action != w.Action || got.Context != w.Context || got.Target != w.Target ||
+			(w.Value != nil && got.Value != w.Value) {
+			t.Errorf("migrateConfig() change %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	config, err := migrationConfigMap(migrated)
+	if err != nil {
+		t.Fatalf("migrationConfigMap() error = %v", err)
+	}
+
+	test := findListItem(config["namespaces"].([]any), "test").(map[string]any)
+	engine := test["storage-engine"].(map[string]any)
+
+	if engine["data-size"] != int64(4<<30) {
+		t.Errorf("migrated storage-engine data-size = %v, want %d", engine["data-size"], int64(4<<30))
+	}
+
+	if _, ok := test["memory-size"]; ok {
+		t.Errorf("migrated namespace still has memory-size: %v", test)
+	}
+
+	bar := findListItem(config["namespaces"].([]any), "bar").(map[string]any)
+	if val, _ := lookupPath(bar, "storage-engine.evict-used-pct"); val != int64(50) {
+		t.Errorf("migrated storage-engine evict-used-pct = %v, want 50", val)
+	}
+
+	if _, ok := config["service"].(map[string]any)["old-setting"]; ok {
+		t.Errorf("migrated service still has old-setting: %v", config["service"])
+	}
+}
+
+func TestMigrationApplies(t *testing.T) {
+	tests := []struct {
+		version  string
+		from, to string
+		want     bool
+	}{
+		{version: "7.0.0", from: "6.4.0", to: "7.0.0", want: true},
+		{version: "7.0.0", from: "6.4.0", to: "8.1.0", want: true},
+		{version: "7.0.0", from: "7.0.0", to: "7.2.0", want: false},
+		{version: "7.0.0", from: "6.0.0", to: "6.4.0", want: false},
+	}
+
+	for _, tt := range tests {
+		got, err := migrationApplies(tt.version, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("migrationApplies() error = %v", err)
+		}
+
+		if got != tt.want {
+			t.Errorf("migrationApplies(%s, %s, %s) = %v, want %v", tt.version, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestRunEMigrate(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "aerospike.conf")
+	scSrc := filepath.Join(dir, "sc.conf")
+
+	if err := os.WriteFile(src, []byte("service {\n\tproto-fd-max 20000\n}\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// schema valid, but rejected by the strong-consistency-default-ttl rule of validate
+	scConf := "service {\n\tproto-fd-max 20000\n}\nnamespace test {\n\treplication-factor 2\n" +
+		"\tstrong-consistency true\n\tdefault-ttl 100\n}\n"
+	if err := os.WriteFile(scSrc, []byte(scConf), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		flags       []string
+		arguments   []string
+		expectError bool
+	}{
+		{name: "too many args", flags: []string{"--from", "6.4.0", "--to", "7.0.0"}, arguments: []string{src, src}, expectError: true},
+		{name: "missing to", flags: []string{"--from", "6.4.0"}, arguments: []string{src}, expectError: true},
+		{name: "missing from", flags: []string{"--to", "7.0.0"}, arguments: []string{src}, expectError: true},
+		{name: "downgrade", flags: []string{"--from", "7.0.0", "--to", "6.4.0"}, arguments: []string{src}, expectError: true},
+		{name: "same version", flags: []string{"--from", "7.0.0", "--to", "7.0.0"}, arguments: []string{src}, expectError: true},
+		{name: "unsupported version", flags: []string{"--from", "1.0.0", "--to", "7.0.0"}, arguments: []string{src}, expectError: true},
+		{name: "missing file", flags: []string{"--from", "6.4.0", "--to", "7.0.0"}, arguments: []string{"./fake_file.conf"}, expectError: true},
+		{
+			name:      "migrate",
+			flags:     []string{"--from", "6.4.0", "--to", "7.0.0", "--output", filepath.Join(dir, "out.conf")},
+			arguments: []string{src},
+		},
+		{
+			name:      "migrate only checks the schema",
+			flags:     []string{"--from", "6.4.0", "--to", "7.0.0", "--output", filepath.Join(dir, "sc-out.conf")},
+			arguments: []string{scSrc},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newMigrateCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			cmd.ParseFlags(tt.flags)
+			err := cmd.RunE(cmd, tt.arguments)
+			if tt.expectError == (err == nil) {
+				t.Fatalf("expectError: %v does not match err: %v", tt.expectError, err)
+			}
+		})
+	}
+
+	out, err := os.ReadFile(filepath.Join(dir, "out.conf"))
+	if err != nil {
+		t.Fatalf("migrate did not write output: %v", err)
+	}
+
+	version, err := getMetaDataItem(out, metaKeyAerospikeVersion)
+	if err != nil || version != "7.0.0" {
+		t.Errorf("migrated metadata version = %s, %v, want 7.0.0", version, err)
+	}
+}
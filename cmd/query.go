@@ -0,0 +1,442 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const queryArgMin = 2
+
+var (
+	errQueryTooFewArgs  = fmt.Errorf("query requires at least %d arguments: <expression> <path/to/config_file>...", queryArgMin)
+	errInvalidQuery     = errors.New("invalid query expression")
+	queryOutputFormats  = []string{outputFormatText, outputFormatJSON}
+	queryFilterOperator = []string{"!=", "=="}
+)
+
+func newQueryCmd() *cobra.Command {
+	res := &cobra.Command{
+		Use:   "query [flags] <expression> <path/to/config_file>...",
+		Short: "Query Aerospike configuration files with path expressions.",
+		Long: `Query evaluates a path expression over configuration files and prints
+				every matching value tagged with its file and context.
+				Expressions are dotted paths of the yaml configuration, where
+				  key or ["key"]  selects a setting, a section or a named list item,
+				  * or [*]        selects every list item or section setting,
+				  [N]             selects the Nth list item,
+				  [?cond]         selects the list items that match cond.
+				Conditions are relative paths, such as storage-engine.type==device,
+				compared with == or !=, or alone to test that the path exists.
+				Conditions can be joined with &&.
+				Results are printed as text, or as json with --output-format json.
+				File patterns are expanded when the shell does not expand them, and
+				directories are searched recursively for configuration files.`,
+		Example: `
+				# Print the storage engine type of every namespace
+				asconfig query 'namespaces[*].storage-engine.type' *.conf
+				# List the namespaces that use device storage with data in memory
+				asconfig query 'namespaces[?storage-engine.type==device && storage-engine.data-in-memory==true].name' *.conf
+				# Print the service section of a yaml config as json
+				asconfig query --output-format json service aerospike.yaml`,
+		RunE: runQueryCommand,
+	}
+
+	res.Flags().
+		StringP("format", "F", "conf", "The format of the source file(s). Valid options are: yaml, yml, json, toml, hcl, and conf.")
+	res.Flags().
+		String("output-format", outputFormatText, "The format of the results. Valid options are: text and json.")
+
+	res.Version = VERSION
+
+	return res
+}
+
+// queryResult is a value matched by a query.
+type queryResult struct {
+	File    string `json:"file"`
+	Context string `json:"context"`
+	Value   any    `json:"value"`
+}
+
+func runQueryCommand(cmd *cobra.Command, args []string) error {
+	logger.Debug("Running query command")
+
+	if len(args) < queryArgMin {
+		return errQueryTooFewArgs
+	}
+
+	outFmt, err := getOutputFormat(cmd, queryOutputFormats...)
+	if err != nil {
+		return err
+	}
+
+	query, err := parseQuery(args[0])
+	if err != nil {
+		return err
+	}
+
+	paths, err := expandValidateSources(args[1:])
+	if err != nil {
+		return err
+	}
+
+	results := []queryResult{}
+
+	for _, path := range paths {
+		config, _, _, err := loadEditConfig(cmd, path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		for _, pointer := range query.evaluate(config) {
+			val, _ := getValueByJSONPath(config, pointer)
+
+			results = append(results, queryResult{
+				File:    path,
+				Context: pointerContext(config, pointer),
+				Value:   val,
+			})
+		}
+	}
+
+	return renderQueryResults(cmd.OutOrStdout(), outFmt, results)
+}
+
+// querySegmentKind is the kind of a step of a query expression.
+type querySegmentKind int
+
+const (
+	queryKey querySegmentKind = iota
+	queryWildcard
+	queryIndex
+	queryFilter
+)
+
+// queryCondition is a condition of a query filter.
+type queryCondition struct {
+	// pointer is the json pointer of the compared value, relative to the list item
+	pointer  string
+	operator string
+	value    string
+}
+
+// querySegment is a step of a query expression.
+type querySegment struct {
+	kind       querySegmentKind
+	key        string
+	index      int
+	conditions []queryCondition
+}
+
+// query is a parsed query expression.
+type query []querySegment
+
+// parseQuery parses a query expression such as namespaces[*].storage-engine.type.
+func parseQuery(expr string) (query, error) {
+	var res query
+
+	for i := 0; i < len(expr); {
+		switch expr[i] {
+		case '.':
+			if i == 0 || i == len(expr)-1 || expr[i+1] == '.' || expr[i+1] == '[' {
+				return nil, fmt.Errorf("%w: unexpected '.' at %d in %s", errInvalidQuery, i, expr)
+			}
+
+			i++
+		case '[':
+			end := closingBracket(expr, i)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unclosed '[' at %d in %s", errInvalidQuery, i, expr)
+			}
+
+			seg, err := parseQueryBracket(expr[i+1 : end])
+			if err != nil {
+				return nil, fmt.Errorf("%w in %s", err, expr)
+			}
+
+			res = append(res, seg)
+			i = end + 1
+		default:
+			end := strings.IndexAny(expr[i:], ".[")
+			if end < 0 {
+				end = len(expr) - i
+			}
+
+			key := expr[i : i+end]
+			if key == "*" {
+				res = append(res, querySegment{kind: queryWildcard})
+			} else {
+				res = append(res, querySegment{kind: queryKey, key: key})
+			}
+
+			i += end
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("%w: empty expression", errInvalidQuery)
+	}
+
+	return res, nil
+}
+
+// closingBracket returns the index of the ']' that closes the '[' at start,
+// ignoring brackets in quoted strings, or -1.
+func closingBracket(expr string, start int) int {
+	var quote byte
+
+	for i := start + 1; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ']':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// parseQueryBracket parses the content of a [...] query step.
+func parseQueryBracket(content string) (querySegment, error) {
+	content = strings.TrimSpace(content)
+
+	switch {
+	case content == "*":
+		return querySegment{kind: queryWildcard}, nil
+	case strings.HasPrefix(content, "?"):
+		conditions, err := parseQueryConditions(content[1:])
+		if err != nil {
+			return querySegment{}, err
+		}
+
+		return querySegment{kind: queryFilter, conditions: conditions}, nil
+	case isQuoted(content):
+		return querySegment{kind: queryKey, key: content[1 : len(content)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil || index < 0 {
+		return querySegment{}, fmt.Errorf("%w: invalid step [%s]", errInvalidQuery, content)
+	}
+
+	return querySegment{kind: queryIndex, index: index}, nil
+}
+
+// parseQueryConditions parses the && separated conditions of a filter.
+func parseQueryConditions(filter string) ([]queryCondition, error) {
+	var res []queryCondition
+
+	for _, cond := range strings.Split(filter, "&&") {
+		cond = strings.TrimSpace(cond)
+
+		condition := queryCondition{}
+		path := cond
+
+		for _, op := range queryFilterOperator {
+			if lhs, rhs, ok := strings.Cut(cond, op); ok {
+				path = strings.TrimSpace(lhs)
+				condition.operator = op
+				condition.value = strings.TrimSpace(rhs)
+
+				if isQuoted(condition.value) {
+					condition.value = condition.value[1 : len(condition.value)-1]
+				}
+
+				break
+			}
+		}
+
+		path = strings.TrimPrefix(strings.TrimPrefix(path, "@"), ".")
+		if path == "" {
+			return nil, fmt.Errorf("%w: invalid condition %q", errInvalidQuery, cond)
+		}
+
+		condition.pointer = "/" + strings.ReplaceAll(path, ".", "/")
+		res = append(res, condition)
+	}
+
+	return res, nil
+}
+
+// isQuoted reports whether s is a single or double quoted string.
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0]
+}
+
+// evaluate returns the json pointers of the values of config matched by the query.
+func (q query) evaluate(config map[string]any) []string {
+	pointers := []string{""}
+
+	for _, seg := range q {
+		var next []string
+
+		for _, pointer := range pointers {
+			val, ok := getValueByJSONPath(config, pointer)
+			if !ok {
+				continue
+			}
+
+			next = append(next, seg.match(pointer, val)...)
+		}
+
+		pointers = next
+	}
+
+	return pointers
+}
+
+// match returns the pointers of the children of val, at pointer, selected by the step.
+func (seg querySegment) match(pointer string, val any) []string {
+	var res []string
+
+	switch seg.kind {
+	case queryKey:
+		switch v := val.(type) {
+		case map[string]any:
+			if _, ok := v[seg.key]; ok {
+				res = append(res, pointer+"/"+seg.key)
+			}
+		case []any:
+			// named list items are selected by name, like in contexts
+			if i := listItemIndex(v, seg.key); i >= 0 {
+				res = append(res, pointer+"/"+strconv.Itoa(i))
+			}
+		}
+	case queryIndex:
+		if list, ok := val.([]any); ok && seg.index < len(list) {
+			res = append(res, pointer+"/"+strconv.Itoa(seg.index))
+		}
+	case queryWildcard, queryFilter:
+		switch v := val.(type) {
+		case []any:
+			for i, item := range v {
+				if seg.accepts(item) {
+					res = append(res, pointer+"/"+strconv.Itoa(i))
+				}
+			}
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				if seg.accepts(v[key]) {
+					res = append(res, pointer+"/"+key)
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// accepts reports whether item matches the conditions of the step.
+func (seg querySegment) accepts(item any) bool {
+	if seg.kind != queryFilter {
+		return true
+	}
+
+	m, ok := item.(map[string]any)
+	if !ok {
+		return false
+	}
+
+	for _, cond := range seg.conditions {
+		val, found := getValueByJSONPath(m, cond.pointer)
+
+		switch cond.operator {
+		case "==":
+			if !found || fmt.Sprint(val) != cond.value {
+				return false
+			}
+		case "!=":
+			if found && fmt.Sprint(val) == cond.value {
+				return false
+			}
+		default:
+			if !found {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// pointerContext returns the dotted context of a json pointer in config, with
+// the names of named list items instead of their indexes.
+// Ex: /namespaces/0/replication-factor is namespaces.test.replication-factor.
+func pointerContext(config map[string]any, pointer string) string {
+	var (
+		parts   []string
+		current any = config
+	)
+
+	for _, part := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		next, ok := processPathPart(current, part)
+		if !ok {
+			break
+		}
+
+		name := part
+		if _, isList := current.([]any); isList {
+			if item, ok := next.(map[string]any); ok {
+				if itemName, ok := item[keyNameField].(string); ok {
+					name = itemName
+				}
+			}
+		}
+
+		parts = append(parts, name)
+		current = next
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// renderQueryResults writes the query results in outFmt.
+func renderQueryResults(w io.Writer, outFmt string, results []queryResult) error {
+	if outFmt == outputFormatJSON {
+		return renderStructured(w, outFmt, results)
+	}
+
+	for _, res := range results {
+		if _, err := fmt.Fprintf(w, "%s: %s = %s\n", res.File, res.Context, queryValueText(res.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queryValueText formats a query value on a single line.
+func queryValueText(val any) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
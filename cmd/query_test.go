@@ -0,0 +1,204 @@
+//go:build unit
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func testQueryConfig() map[string]any {
+	return map[string]any{
+		"service": map[string]any{"proto-fd-max": int64(15000), "cluster-name": "cl1"},
+		"namespaces": []any{
+			map[string]any{
+				"name":           "test",
+				"storage-engine": map[string]any{"type": "memory"},
+			},
+			map[string]any{
+				"name": "dev",
+				"storage-engine": map[string]any{
+					"type":           "device",
+					"data-in-memory": true,
+				},
+			},
+		},
+	}
+}
+
+func TestQueryEvaluate(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "wildcard list items",
+			expr: "namespaces[*].storage-engine.type",
+			want: []string{"namespaces.test.storage-engine.type", "namespaces.dev.storage-engine.type"},
+		},
+		{
+			name: "wildcard section settings",
+			expr: "service.*",
+			want: []string{"service.cluster-name", "service.proto-fd-max"},
+		},
+		{
+			name: "index and quoted key",
+			expr: `namespaces[1]["storage-engine"].type`,
+			want: []string{"namespaces.dev.storage-engine.type"},
+		},
+		{
+			name: "named list item",
+			expr: "namespaces.test.name",
+			want: []string{"namespaces.test.name"},
+		},
+		{
+			name: "filter",
+			expr: "namespaces[?storage-engine.type==device && storage-engine.data-in-memory==true].name",
+			want: []string{"namespaces.dev.name"},
+		},
+		{
+			name: "filter not equal and exists",
+			expr: "namespaces[?storage-engine.type!='device' && name].name",
+			want: []string{"namespaces.test.name"},
+		},
+		{
+			name: "no match",
+			expr: "network.service.port",
+		},
+		{
+			name:    "unclosed bracket",
+			expr:    "namespaces[*",
+			wantErr: errInvalidQuery,
+		},
+		{
+			name:    "invalid step",
+			expr:    "namespaces[first]",
+			wantErr: errInvalidQuery,
+		},
+		{
+			name:    "empty key",
+			expr:    "service..proto-fd-max",
+			wantErr: errInvalidQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := parseQuery(tt.expr)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseQuery() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			config := testQueryConfig()
+
+			var got []string
+			for _, pointer := range q.evaluate(config) {
+				got = append(got, pointerContext(config, pointer))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunQueryCommand(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.conf": "namespace test {\n\tstorage-engine memory\n}\n",
+		"b.yaml": "namespaces:\n  - name: bar\n    storage-engine:\n      type: device\n      files: [/opt/bar.dat]\n",
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+	}
+
+	aConf, bYAML := filepath.Join(dir, "a.conf"), filepath.Join(dir, "b.yaml")
+
+	tests := []struct {
+		name    string
+		flags   []string
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "text",
+			args: []string{"namespaces[*].storage-engine.type", aConf, bYAML},
+			want: aConf + ": namespaces.test.storage-engine.type = memory\n" +
+				bYAML + ": namespaces.bar.storage-engine.type = device\n",
+		},
+		{
+			name: "file pattern",
+			args: []string{"namespaces[*].storage-engine.files", filepath.Join(dir, "*.yaml")},
+			want: bYAML + ": namespaces.bar.storage-engine.files = [\"/opt/bar.dat\"]\n",
+		},
+		{
+			name:  "json",
+			flags: []string{"--output-format", "json"},
+			args:  []string{"namespaces[?storage-engine.type==memory].name", aConf, bYAML},
+			want: "[\n  {\n    \"file\": \"" + aConf + "\",\n    \"context\": \"namespaces.test.name\",\n" +
+				"    \"value\": \"test\"\n  }\n]\n",
+		},
+		{
+			name: "directory and duplicate file",
+			args: []string{"namespaces[*].name", dir, bYAML},
+			want: aConf + ": namespaces.test.name = test\n" + bYAML + ": namespaces.bar.name = bar\n",
+		},
+		{
+			name:    "pattern without matches",
+			args:    []string{"namespaces", filepath.Join(dir, "*.json")},
+			wantErr: errNoFilesMatched,
+		},
+		{
+			name:    "too few arguments",
+			args:    []string{"namespaces"},
+			wantErr: errQueryTooFewArgs,
+		},
+		{
+			name:    "invalid output format",
+			flags:   []string{"--output-format", "yaml"},
+			args:    []string{"namespaces", aConf},
+			wantErr: errInvalidOutputFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newQueryCmd()
+
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+
+			if err := cmd.ParseFlags(tt.flags); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+
+			err := cmd.RunE(cmd, tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunE() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if out.String() != tt.want {
+				t.Errorf("RunE() output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -97,10 +97,20 @@ func Execute() error {
 	}
 
 	// Register subcommands
+	rootCmd.AddCommand(newComposeCmd())
 	rootCmd.AddCommand(newConvertCmd())
 	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.AddCommand(newExplainCmd())
 	rootCmd.AddCommand(newGenerateCmd())
+	rootCmd.AddCommand(newGetCmd())
+	rootCmd.AddCommand(newHistoryCmd())
+	rootCmd.AddCommand(newLintCmd())
 	rootCmd.AddCommand(newListCmd())
+	rootCmd.AddCommand(newMergeCmd())
+	rootCmd.AddCommand(newMigrateCmd())
+	rootCmd.AddCommand(newQueryCmd())
+	rootCmd.AddCommand(newSetCmd())
+	rootCmd.AddCommand(newUnsetCmd())
 	rootCmd.AddCommand(newValidateCmd())
 
 	err := rootCmd.Execute()
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	lib "github.com/aerospike/aerospike-management-lib"
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+
+	"github.com/aerospike/asconfig/schema"
+)
+
+// Config keys that identify list items and typed sections.
+const (
+	keyNameField = "name"
+	keyTypeField = "type"
+)
+
+// Schema combinator keywords whose alternatives can define properties.
+var schemaCombinators = []string{"oneOf", "anyOf", "allOf"}
+
+// sortVersions sorts Aerospike server versions in ascending order.
+func sortVersions(versions []string) {
+	sort.Slice(versions, func(i, j int) bool {
+		cmp, compErr := lib.CompareVersions(versions[i], versions[j])
+		if compErr != nil {
+			// Fall back to lexical order if comparison fails
+			logger.Warnf("Falling back to lexical version sort: %v", compErr)
+			return versions[i] < versions[j]
+		}
+		return cmp < 0
+	})
+}
+
+// schemaVersions returns the versions in schemaMap in ascending order.
+func schemaVersions(schemaMap schema.SchemaMap) []string {
+	versions := make([]string, 0, len(schemaMap))
+	for version := range schemaMap {
+		versions = append(versions, version)
+	}
+
+	sortVersions(versions)
+
+	return versions
+}
+
+// loadSchema returns the parsed json schema for the base version of version.
+func loadSchema(schemaMap schema.SchemaMap, version string) (map[string]any, error) {
+	base, err := asConf.BaseVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidAerospikeVersion, version)
+	}
+
+	schemaStr, ok := schemaMap[base]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedAerospikeVersion, version)
+	}
+
+	var res map[string]any
+	if err := json.Unmarshal([]byte(schemaStr), &res); err != nil {
+		return nil, fmt.Errorf("failed to parse schema for version %s: %w", base, err)
+	}
+
+	return res, nil
+}
+
+// schemaProperty is a property definition and its json pointer within a schema.
+type schemaProperty struct {
+	node    map[string]any
+	pointer string
+}
+
+// schemaProperties returns the properties defined by the schema node at pointer,
+// including those defined by the alternatives of oneOf, anyOf and allOf.
+// The first definition of a property is kept.
+func schemaProperties(node map[string]any, pointer string) map[string]schemaProperty {
+	res := map[string]schemaProperty{}
+
+	if props, ok := node[propertiesField].(map[string]any); ok {
+		for key, prop := range props {
+			if propMap, ok := prop.(map[string]any); ok {
+				res[key] = schemaProperty{node: propMap, pointer: pointer + "/" + propertiesField + "/" + key}
+			}
+		}
+	}
+
+	for _, keyword := range schemaCombinators {
+		alternatives, _ := node[keyword].([]any)
+
+		for i, alt := range alternatives {
+			altMap, ok := alt.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			altPointer := pointer + "/" + keyword + "/" + strconv.Itoa(i)
+
+			for key, prop := range schemaProperties(altMap, altPointer) {
+				if _, ok := res[key]; !ok {
+					res[key] = prop
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// schemaItems returns the item definition of an array schema node at pointer.
+func schemaItems(node map[string]any, pointer string) (schemaProperty, bool) {
+	items, ok := node[itemsField].(map[string]any)
+	if !ok {
+		return schemaProperty{}, false
+	}
+
+	return schemaProperty{node: items, pointer: pointer + "/" + itemsField}, true
+}
+
+// schemaPropertyAt returns the definition of the setting at the dotted config
+// path, descending into the items of list sections such as namespaces.
+func schemaPropertyAt(node map[string]any, path string) (schemaProperty, bool) {
+	prop := schemaProperty{node: node}
+
+	for _, key := range strings.Split(path, ".") {
+		if items, ok := schemaItems(prop.node, prop.pointer); ok {
+			prop = items
+		}
+
+		child, ok := schemaProperties(prop.node, prop.pointer)[key]
+		if !ok {
+			return schemaProperty{}, false
+		}
+
+		prop = child
+	}
+
+	return prop, true
+}
+
+// schemaSettingPath returns the schema path of a dotted config path. Names of
+// list items are dropped so that contexts such as namespaces.test.replication-factor
+// can be used for namespaces.replication-factor.
+func schemaSettingPath(node map[string]any, path string) (string, bool) {
+	prop := schemaProperty{node: node}
+
+	var res []string
+
+	keys := strings.Split(path, ".")
+
+	for i, key := range keys {
+		inList := false
+		if items, ok := schemaItems(prop.node, prop.pointer); ok {
+			prop = items
+			inList = true
+		}
+
+		child, ok := schemaProperties(prop.node, prop.pointer)[key]
+		if !ok {
+			// an item name, the next key is a setting of the item
+			if inList && i < len(keys)-1 && res[len(res)-1] != "" {
+				res = append(res, "")
+				continue
+			}
+
+			return "", false
+		}
+
+		res = append(res, key)
+		prop = child
+	}
+
+	return strings.Join(slices.DeleteFunc(res, func(key string) bool { return key == "" }), "."), true
+}
+
+// Kinds of events in the history of a setting.
+const (
+	settingAdded   = "added"
+	settingRemoved = "removed"
+	settingChanged = "changed"
+)
+
+// settingFields are the schema fields compared across versions.
+var settingFields = []string{"type", "default", "minimum", "maximum", "enum", "dynamic", "enterpriseOnly"}
+
+// settingFieldChange is a change of a schema field of a setting.
+type settingFieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old,omitempty"`
+	New   any    `json:"new,omitempty"`
+}
+
+// settingEvent is the addition, removal or change of a setting in a server version.
+// The changes of an addition are the fields of the added setting.
+type settingEvent struct {
+	Version string               `json:"version"`
+	Kind    string               `json:"kind"`
+	Changes []settingFieldChange `json:"changes,omitempty"`
+}
+
+// settingHistory returns the events of the setting at the schema path across
+// the versions of schemaMap, in version order.
+func settingHistory(schemaMap schema.SchemaMap, path string) ([]settingEvent, error) {
+	var (
+		res  []settingEvent
+		prev map[string]any
+	)
+
+	for _, version := range schemaVersions(schemaMap) {
+		node, err := loadSchema(schemaMap, version)
+		if err != nil {
+			return nil, err
+		}
+
+		prop, ok := schemaPropertyAt(node, path)
+
+		switch {
+		case ok && prev == nil:
+			res = append(res, settingEvent{
+				Version: version,
+				Kind:    settingAdded,
+				Changes: settingFieldChanges(nil, prop.node),
+			})
+		case !ok && prev != nil:
+			res = append(res, settingEvent{Version: version, Kind: settingRemoved})
+		case ok:
+			if changes := settingFieldChanges(prev, prop.node); len(changes) > 0 {
+				res = append(res, settingEvent{Version: version, Kind: settingChanged, Changes: changes})
+			}
+		}
+
+		prev = prop.node
+	}
+
+	return res, nil
+}
+
+// settingFieldChanges returns the changes of the compared fields from old to updated.
+func settingFieldChanges(old, updated map[string]any) []settingFieldChange {
+	var res []settingFieldChange
+
+	for _, field := range settingFields {
+		if !reflect.DeepEqual(old[field], updated[field]) {
+			res = append(res, settingFieldChange{Field: field, Old: old[field], New: updated[field]})
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// templateVarRegex matches the ${VAR} placeholders of a config template.
+// Nested values are referenced with dotted names, e.g. ${node.rack-id}.
+var templateVarRegex = regexp.MustCompile(`\$\{([^{}]*)\}`)
+
+var (
+	errInvalidTemplateValues = errors.New("template values must be a yaml map")
+	errInvalidTemplateSet    = errors.New("--set must be in the form key=value")
+	errUnresolvedTemplateVar = errors.New("unresolved template variables")
+	errRenderTemplate        = errors.New("unable to render config template")
+)
+
+// getTemplateFlags returns the flags used to render config templates.
+func getTemplateFlags() *pflag.FlagSet {
+	res := &pflag.FlagSet{}
+	res.StringSlice("values", nil, "Yaml files of values used to render the source as a template. "+
+		"Later files override earlier ones.")
+	res.StringArray("set", nil, "A template value in the form key=value, e.g. node.rack-id=2. "+
+		"Overrides --values and can be repeated.")
+
+	return res
+}
+
+// getTemplateValues returns the template values from the --values and --set
+// flags. The values are nil if neither is set.
+func getTemplateValues(cmd *cobra.Command) (map[string]any, error) {
+	valuesPaths, err := cmd.Flags().GetStringSlice("values")
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Processing flag values value=%v", valuesPaths)
+
+	sets, err := cmd.Flags().GetStringArray("set")
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Processing flag set value=%v", sets)
+
+	if len(valuesPaths) == 0 && len(sets) == 0 {
+		return nil, nil
+	}
+
+	values := map[string]any{}
+
+	for _, path := range valuesPaths {
+		fileValues, err := loadTemplateValues(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		values, err = composeMaps(values, fileValues)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	for _, set := range sets {
+		key, val, ok := strings.Cut(set, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: %s", errInvalidTemplateSet, set)
+		}
+
+		setTemplateValue(values, strings.Split(key, "."), val)
+	}
+
+	return values, nil
+}
+
+// loadTemplateValues reads a yaml file of template values.
+func loadTemplateValues(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var values any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	if values == nil {
+		return map[string]any{}, nil
+	}
+
+	res, ok := values.(map[string]any)
+	if !ok {
+		return nil, errInvalidTemplateValues
+	}
+
+	return res, nil
+}
+
+// setTemplateValue sets the value at the dotted key path, replacing values that
+// are in the way of the path.
+func setTemplateValue(values map[string]any, path []string, val string) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := values[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			values[key] = next
+		}
+
+		values = next
+	}
+
+	values[path[len(path)-1]] = val
+}
+
+// lookupTemplateValue returns the value at a dotted name, such as node.rack-id.
+func lookupTemplateValue(values map[string]any, name string) (any, bool) {
+	var val any = values
+
+	for _, key := range strings.Split(name, ".") {
+		section, ok := val.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		val, ok = section[key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return val, true
+}
+
+// renderConfigTemplate renders the config source as a template with values when
+// it has ${VAR} placeholders or Go template actions. Sources are returned as is
+// when values is nil, so plain configs that contain ${ or {{ are not rendered.
+func renderConfigTemplate(data []byte, values map[string]any) ([]byte, error) {
+	if values == nil {
+		return data, nil
+	}
+
+	if !bytes.Contains(data, []byte("{{")) && !templateVarRegex.Match(data) {
+		return data, nil
+	}
+
+	return renderTemplate(data, values)
+}
+
+// renderTemplate executes the Go template actions of data with values, then
+// substitutes its ${VAR} placeholders. Variables that are not in values are errors.
+func renderTemplate(data []byte, values map[string]any) ([]byte, error) {
+	tmpl, err := template.New("config").Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, errors.Join(errRenderTemplate, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return nil, errors.Join(errRenderTemplate, err)
+	}
+
+	var unresolved []string
+
+	res := templateVarRegex.ReplaceAllFunc(buf.Bytes(), func(match []byte) []byte {
+		name := strings.TrimSpace(string(templateVarRegex.FindSubmatch(match)[1]))
+
+		val, ok := lookupTemplateValue(values, name)
+		if !ok || val == nil {
+			if !slices.Contains(unresolved, name) {
+				unresolved = append(unresolved, name)
+			}
+
+			return match
+		}
+
+		return []byte(fmt.Sprint(val))
+	})
+
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("%w: %s", errUnresolvedTemplateVar, strings.Join(unresolved, ", "))
+	}
+
+	return res, nil
+}
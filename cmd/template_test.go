@@ -0,0 +1,246 @@
+//go:build unit
+
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	values := map[string]any{
+		"address": "10.0.0.1",
+		"node":    map[string]any{"id": "a1", "rack-id": 2, "devices": []any{"/dev/sdb", "/dev/sdc"}},
+		"empty":   nil,
+	}
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "variables",
+			tmpl: "service {\n\tnode-id ${node.id}\n}\nnetwork {\n\tservice {\n\t\taddress ${ address }\n\t}\n}\n",
+			want: "service {\n\tnode-id a1\n}\nnetwork {\n\tservice {\n\t\taddress 10.0.0.1\n\t}\n}\n",
+		},
+		{
+			name: "go template",
+			tmpl: "rack-id {{ index .node \"rack-id\" }}\n{{ range .node.devices }}device {{ . }}\n{{ end }}",
+			want: "rack-id 2\ndevice /dev/sdb\ndevice /dev/sdc\n",
+		},
+		{name: "no placeholders", tmpl: "service {\n\tproto-fd-max 15000\n}\n", want: "service {\n\tproto-fd-max 15000\n}\n"},
+		{name: "unresolved variables", tmpl: "${node.name} ${empty} ${node.name}", wantErr: errUnresolvedTemplateVar},
+		{name: "unresolved go template key", tmpl: "{{ .missing }}", wantErr: errRenderTemplate},
+		{name: "invalid go template", tmpl: "{{ .node ", wantErr: errRenderTemplate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderTemplate([]byte(tt.tmpl), values)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("renderTemplate() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && string(got) != tt.want {
+				t.Errorf("renderTemplate() = %q, want %q", got, tt.want)
+			}
+
+			if tt.wantErr == errUnresolvedTemplateVar && !strings.HasSuffix(err.Error(), ": node.name, empty") {
+				t.Errorf("renderTemplate() error = %v, want the unresolved variables listed once", err)
+			}
+		})
+	}
+}
+
+func TestRenderConfigTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		values  map[string]any
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "not a template",
+			src:    "service {\n\tproto-fd-max 15000\n}\n",
+			values: map[string]any{},
+			want:   "service {\n\tproto-fd-max 15000\n}\n",
+		},
+		{name: "no values", src: "node-id ${node.id} # {{ not a template }}\n", want: "node-id ${node.id} # {{ not a template }}\n"},
+		{name: "placeholder without a value", src: "node-id ${node.id}\n", values: map[string]any{}, wantErr: errUnresolvedTemplateVar},
+		{
+			name:    "go template without a value",
+			src:     "proto-fd-max {{ .proto_fd_max }}\n",
+			values:  map[string]any{},
+			wantErr: errRenderTemplate,
+		},
+		{
+			name:   "values",
+			src:    "node-id ${node.id}\n",
+			values: map[string]any{"node": map[string]any{"id": "a1"}},
+			want:   "node-id a1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderConfigTemplate([]byte(tt.src), tt.values)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("renderConfigTemplate() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("renderConfigTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunETemplate(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	dir := t.TempDir()
+	paths := map[string]string{
+		"aerospike.tmpl.conf": "service {\n\tnode-id ${node.id}\n\tproto-fd-max {{ .proto_fd_max }}\n}\n" +
+			"logging {\n\tconsole {\n\t\tcontext any info\n\t}\n}\n" +
+			"namespace test {\n\track-id ${node.rack-id}\n\treplication-factor 2\n\tstorage-engine memory\n}\n",
+		"common.yaml": "proto_fd_max: 15000\nnode:\n  id: a1\n  rack-id: 1\n",
+		"node-2.yaml": "node:\n  id: a2\n",
+		"list.yaml":   "- a\n- b\n",
+	}
+
+	for name, data := range paths {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	path := func(name string) string { return filepath.Join(dir, name) }
+	tmplPath := path("aerospike.tmpl.conf")
+
+	tests := []struct {
+		name       string
+		flags      []string
+		wantErr    error
+		wantOutput []string
+	}{
+		{
+			name:       "values files",
+			flags:      []string{"--values", path("common.yaml"), "--values", path("node-2.yaml")},
+			wantOutput: []string{"node-id: a2", "proto-fd-max: 15000", "rack-id: 1"},
+		},
+		{
+			name:       "set overrides values",
+			flags:      []string{"--values", path("common.yaml"), "--set", "node.rack-id=3", "--set", "node.id=a3"},
+			wantOutput: []string{"node-id: a3", "rack-id: 3"},
+		},
+		{name: "unresolved", flags: []string{"--set", "proto_fd_max=15000"}, wantErr: errUnresolvedTemplateVar},
+		{name: "invalid set", flags: []string{"--set", "node.id"}, wantErr: errInvalidTemplateSet},
+		{name: "invalid values", flags: []string{"--values", path("list.yaml")}, wantErr: errInvalidTemplateValues},
+	}
+
+	for _, tt := range tests {
+		t.Run("convert "+tt.name, func(t *testing.T) {
+			outPath := filepath.Join(t.TempDir(), "aerospike.yaml")
+
+			cmd := newConvertCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			cmd.ParseFlags(append(tt.flags, "--aerospike-version", "7.0.0", "--output", outPath))
+
+			err := cmd.PreRunE(cmd, []string{tmplPath})
+			if err == nil {
+				err = cmd.RunE(cmd, []string{tmplPath})
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("convert error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			out, err := os.ReadFile(outPath)
+			if err != nil {
+				t.Fatalf("Failed to read converted config: %v", err)
+			}
+
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("convert output = %s, want it to contain %q", out, want)
+				}
+			}
+		})
+
+		t.Run("validate "+tt.name, func(t *testing.T) {
+			cmd := newValidateCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			cmd.ParseFlags(append(tt.flags, "--aerospike-version", "7.0.0"))
+			if err := cmd.RunE(cmd, []string{tmplPath}); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validate error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunENoTemplateValues(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	// a plain config with template characters is not rendered without --values or --set
+	srcPath := filepath.Join(t.TempDir(), "aerospike.conf")
+	src := "# copied from ${HOME}/aerospike.conf, do not add {{ actions }}\n" +
+		"service {\n\tproto-fd-max 15000\n}\n" +
+		"logging {\n\tfile /var/log/aerospike/${HOSTNAME}.log {\n\t\tcontext any info\n\t}\n}\n" +
+		"namespace test {\n\treplication-factor 2\n\tstorage-engine memory\n}\n"
+	if err := os.WriteFile(srcPath, []byte(src), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	outPath := filepath.Join(t.TempDir(), "aerospike.yaml")
+
+	convert := newConvertCmd()
+	convert.SetOut(io.Discard)
+	convert.SetErr(io.Discard)
+	convert.ParseFlags([]string{"--aerospike-version", "7.0.0", "--output", outPath})
+
+	err := convert.PreRunE(convert, []string{srcPath})
+	if err == nil {
+		err = convert.RunE(convert, []string{srcPath})
+	}
+
+	if err != nil {
+		t.Fatalf("convert error = %v, want nil", err)
+	}
+
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("Failed to read converted config: %v", err)
+	}
+
+	if !strings.Contains(string(out), "/var/log/aerospike/${HOSTNAME}.log") {
+		t.Errorf("convert output = %s, want the source log path", out)
+	}
+
+	validate := newValidateCmd()
+	validate.SetOut(io.Discard)
+	validate.SetErr(io.Discard)
+	validate.ParseFlags([]string{"--aerospike-version", "7.0.0"})
+
+	if err := validate.RunE(validate, []string{srcPath}); err != nil {
+		t.Fatalf("validate error = %v, want nil", err)
+	}
+}
@@ -1,16 +1,23 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
 
+	"github.com/aerospike/asconfig/conf"
 	"github.com/aerospike/asconfig/conf/metadata"
 )
 
@@ -22,6 +29,19 @@ const (
 	metaKeyAsadmVersion     = "asadm-version"
 )
 
+// output formats for command results.
+const (
+	outputFormatText     = "text"
+	outputFormatJSON     = "json"
+	outputFormatSARIF    = "sarif"
+	outputFormatYAML     = "yaml"
+	outputFormatMarkdown = "markdown"
+	outputFormatUnified  = "unified"
+
+	jsonIndent = "  "
+	yamlIndent = 2
+)
+
 var (
 	errTooManyArguments            = fmt.Errorf("expected a maximum of %d arguments", convertArgMax)
 	errFileNotExist                = errors.New("file does not exist")
@@ -30,6 +50,9 @@ var (
 	errUnsupportedAerospikeVersion = errors.New("aerospike version unsupported")
 	errInvalidFormat               = errors.New("invalid format flag")
 	errMissingFormat               = errors.New("missing format flag")
+	errInvalidOutputFormat         = errors.New("invalid output-format flag")
+	errInvalidToFormat             = errors.New("invalid --to format, valid options are: yaml, yml, json, toml, hcl, and conf")
+	errSizeOutOfRange              = errors.New("size is out of range")
 
 	errDiffConfigsDiffer                = errors.New("configuration files are not equal")
 	errMismatchedFileFormats            = errors.New("mismatched file formats")
@@ -51,9 +74,13 @@ var (
 		"diff server requires no more than %d file path as argument",
 		diffServerArgMax,
 	)
+	errDiffNodesTooManyArgs = errors.New("diff nodes does not take arguments")
+	errDiffNodesTooFewHosts = fmt.Errorf("diff nodes requires at least %d hosts", diffNodesHostMin)
 
 	// Schema diff errors.
-	errSchemaDiffWrongArgs  = errors.New("diff versions requires exactly 2 version arguments")
+	errSchemaDiffWrongArgs = errors.New(
+		"diff versions requires exactly 2 version arguments and an optional config file",
+	)
 	errInvalidSchemaVersion = errors.New("invalid schema version")
 
 	errUnableToGenerateConfigFile       = errors.New("unable to generate config file")
@@ -62,7 +89,12 @@ var (
 
 	errInvalidLogLevel = errors.New("invalid log-level flag")
 
-	errValidateTooManyArguments = fmt.Errorf("expected a maximum of %d arguments", convertArgMax)
+	errInvalidJobs    = errors.New("jobs must be at least 1")
+	errInvalidGlob    = errors.New("invalid glob pattern")
+	errNoFilesMatched = errors.New("no configuration files found")
+
+	errInvalidRackCount  = errors.New("rack-count must not be negative")
+	errInvalidHostMemory = errors.New("host-memory must be a size like 64G")
 
 	errMetadataDoesNotContain = errors.New("metadata does not contain key")
 )
@@ -106,8 +138,6 @@ func toString(value any) (string, bool) {
 }
 
 func genMetaDataText(src, msg []byte, mdata map[string]string) ([]byte, error) {
-	metaHeader := "# *** Aerospike Metadata Generated by Asconfig ***"
-
 	err := metadata.Unmarshal(src, mdata)
 	if err != nil {
 		return nil, err
@@ -118,18 +148,37 @@ func genMetaDataText(src, msg []byte, mdata map[string]string) ([]byte, error) {
 		return nil, err
 	}
 
-	metaFooter := "# *** End Aerospike Metadata ***"
 	strMsg := string(msg)
 
 	if len(msg) > 0 {
 		strMsg += "\n#\n"
 	}
 
-	mtext = []byte(fmt.Sprintf("%s\n%s%s%s\n\n", metaHeader, strMsg, mtext, metaFooter))
+	mtext = []byte(fmt.Sprintf("%s\n%s%s%s\n\n", metadata.Header, strMsg, mtext, metadata.Footer))
 
 	return mtext, nil
 }
 
+// addMetaData adds metadata to the config text out. It is written as a comment
+// header, or in the reserved metadata field of json configs which have no comments.
+// The disclaimer msg is only written in comment headers.
+func addMetaData(out []byte, format asConf.Format, src, msg []byte, mdata map[string]string) ([]byte, error) {
+	if format == conf.JSON {
+		if err := metadata.Unmarshal(src, mdata); err != nil {
+			return nil, err
+		}
+
+		return metadata.MarshalJSON(out, mdata)
+	}
+
+	mtext, err := genMetaDataText(src, msg, mdata)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(mtext, out...), nil
+}
+
 func getMetaDataItemOptional(src []byte, key string) (string, error) {
 	mdata := map[string]string{}
 
@@ -197,17 +246,71 @@ func getConfFileFormat(path string, cmd *cobra.Command) (asConf.Format, error) {
 	return asFormat, nil
 }
 
+// getOutputFormat returns the value of the --output-format flag of the cobra command
+// an error is returned if the value is not one of the valid formats.
+func getOutputFormat(cmd *cobra.Command, valid ...string) (string, error) {
+	outFmt, err := cmd.Flags().GetString("output-format")
+	if err != nil {
+		return "", err
+	}
+
+	logger.Debugf("Processing flag output-format value=%s", outFmt)
+
+	outFmt = strings.ToLower(outFmt)
+	if !slices.Contains(valid, outFmt) {
+		return "", fmt.Errorf("%w: %s, valid options are: %s", errInvalidOutputFormat, outFmt, strings.Join(valid, ", "))
+	}
+
+	return outFmt, nil
+}
+
+// renderStructured writes v to w as yaml when outFmt is outputFormatYAML and as
+// indented json otherwise. It is used by every command with structured output.
+func renderStructured(w io.Writer, outFmt string, v any) error {
+	if outFmt == outputFormatYAML {
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(yamlIndent)
+
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+
+		return enc.Close()
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", jsonIndent)
+
+	return enc.Encode(v)
+}
+
 var ErrSilent = errors.New("SILENT")
 
-func ParseFmtString(in string) (asConf.Format, error) {
-	switch strings.ToLower(in) {
-	case "yaml", "yml":
-		return asConf.YAML, nil
-	case "asconfig", "conf", "asconf":
-		return asConf.AeroConfig, nil
-	default:
-		return asConf.Invalid, fmt.Errorf("%w: %s", asConf.ErrInvalidFormat, in)
+// getToFormat returns the format of the configuration written by a command from
+// its --to flag, it defaults to defaultFormat.
+func getToFormat(cmd *cobra.Command, defaultFormat asConf.Format) (asConf.Format, error) {
+	to, err := cmd.Flags().GetString("to")
+	if err != nil {
+		return asConf.Invalid, err
 	}
+
+	logger.Debugf("Processing flag to value=%s", to)
+
+	if to == "" {
+		return defaultFormat, nil
+	}
+
+	outFmt, err := ParseFmtString(to)
+	if err != nil {
+		return asConf.Invalid, fmt.Errorf("%w: %s", errInvalidToFormat, to)
+	}
+
+	return outFmt, nil
+}
+
+// ParseFmtString returns the registered config format with the name in.
+func ParseFmtString(in string) (asConf.Format, error) {
+	return conf.ParseFormat(in)
 }
 
 // ============================================================================
@@ -231,3 +334,38 @@ func renderError(format string, args ...any) {
 func renderWarning(format string, args ...any) {
 	fmt.Fprintf(os.Stderr, "Warning: "+format, args...)
 }
+
+// sizeSuffixes are the multipliers of the size units accepted by parseSize.
+var sizeSuffixes = map[byte]uint64{
+	'K': 1 << 10,
+	'M': 1 << 20,
+	'G': 1 << 30,
+	'T': 1 << 40,
+	'P': 1 << 50,
+}
+
+// parseSize parses a size in bytes with an optional K, M, G, T or P suffix.
+// Ex: 64G.
+func parseSize(val string) (uint64, error) {
+	if val == "" {
+		return 0, nil
+	}
+
+	multiplier := uint64(1)
+
+	if m, ok := sizeSuffixes[strings.ToUpper(val)[len(val)-1]]; ok {
+		multiplier = m
+		val = val[:len(val)-1]
+	}
+
+	n, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if n > math.MaxUint64/multiplier {
+		return 0, fmt.Errorf("%w: %s", errSizeOutOfRange, val)
+	}
+
+	return n * multiplier, nil
+}
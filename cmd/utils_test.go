@@ -3,12 +3,15 @@
 package cmd
 
 import (
+	"math"
 	"reflect"
 	"testing"
 
 	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
 
 	"github.com/spf13/cobra"
+
+	"github.com/aerospike/asconfig/conf"
 )
 
 var mockCmdNoFmt cobra.Command = cobra.Command{}
@@ -58,6 +61,15 @@ func Test_getConfFileFormat(t *testing.T) {
 			want:    asConf.AeroConfig,
 			wantErr: false,
 		},
+		{
+			name: "p4",
+			args: args{
+				path: "conf.json",
+				cmd:  &mockCmdNoFmt,
+			},
+			want:    conf.JSON,
+			wantErr: false,
+		},
 		{
 			name: "n1",
 			args: args{
@@ -81,3 +93,35 @@ func Test_getConfFileFormat(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseSize(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    uint64
+		wantErr bool
+	}{
+		{val: "", want: 0},
+		{val: "1024", want: 1024},
+		{val: "4K", want: 4 << 10},
+		{val: "2m", want: 2 << 20},
+		{val: "64G", want: 64 << 30},
+		{val: "1T", want: 1 << 40},
+		{val: "G", wantErr: true},
+		{val: "12X", wantErr: true},
+		{val: "16383P", want: 16383 << 50},
+		{val: "16384P", wantErr: true},
+		{val: "18446744073709551615", want: math.MaxUint64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseSize(tt.val)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,13 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 
 	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
 	"github.com/spf13/cobra"
@@ -11,19 +17,32 @@ import (
 )
 
 const (
-	validateArgMax = 1
+	// globMetaChars are the characters that make a validate argument a glob pattern.
+	globMetaChars = "*?["
 )
 
 func newValidateCmd() *cobra.Command {
 	res := &cobra.Command{
-		Use:   "validate [flags] <path/to/config_file>",
-		Short: "Validate an Aerospike configuration file.",
-		Long: `Validate an Aerospike configuration file in any supported format
+		Use:   "validate [flags] [path/to/config_file|directory|glob]...",
+		Short: "Validate Aerospike configuration files.",
+		Long: `Validate Aerospike configuration files in any supported format
 				against a versioned Aerospike configuration schema.
 				If a file passes validation nothing is output, otherwise errors
 				indicating problems with the configuration file are shown.
 				If a file path is not provided, validate reads from stdin.
-				Ex: asconfig validate --aerospike-version 7.0.0 aerospike.conf`,
+				Ex: asconfig validate --aerospike-version 7.0.0 aerospike.conf
+				Many files, directories and glob patterns can be validated at once.
+				Directories are searched recursively for .conf, .yaml and .yml files.
+				Each file's server version is read from its own metadata unless
+				--aerospike-version is provided. A result is shown for each file
+				followed by a summary, and the command fails if any file is invalid.
+				Ex: asconfig validate --jobs 8 nodes/ "staging/*.conf"
+				Use --output-format to produce machine readable json or sarif output.
+				Ex: asconfig validate --output-format json aerospike.conf
+				Templates with ${VAR} placeholders or Go template actions are rendered
+				with the values passed to --values and --set before they are validated.
+				Sources are only rendered when --values or --set is passed.
+				Ex: asconfig validate -a 7.0.0 --values node-1.yaml aerospike.tmpl.conf`,
 		RunE: runValidateCommand,
 	}
 
@@ -32,8 +51,19 @@ func newValidateCmd() *cobra.Command {
 	// is in the input config file's metadata
 	commonFlags := getCommonFlags()
 	res.Flags().AddFlagSet(commonFlags)
+	res.Flags().AddFlagSet(getTemplateFlags())
 	res.Flags().
-		StringP("format", "F", "conf", "The format of the source file(s). Valid options are: yaml, yml, and conf.")
+		StringP("format", "F", "conf", "The format of the source file(s). Valid options are: yaml, yml, json, toml, hcl, and conf.")
+	res.Flags().
+		String("output-format", outputFormatText, "The format of the validation results. Valid options are: text, json, and sarif.")
+	res.Flags().
+		IntP("jobs", "j", runtime.NumCPU(), "The maximum number of files to validate concurrently.")
+	res.Flags().
+		Int("rack-count", 0, "The number of racks in the cluster, used to check replication-factor.")
+	res.Flags().
+		String("host-memory", "", "The memory available on each host, Ex: 64G. Used to check namespace memory sizes.")
+	res.Flags().
+		String("policy", "", "Path to a yaml file of house rules that configurations must also follow.")
 
 	res.Version = VERSION
 
@@ -44,48 +74,167 @@ func newValidateCmd() *cobra.Command {
 func runValidateCommand(cmd *cobra.Command, args []string) error {
 	logger.Debug("Running validate command")
 
-	if len(args) > validateArgMax {
-		return errValidateTooManyArguments
+	outFmt, err := getOutputFormat(cmd, outputFormatText, outputFormatJSON, outputFormatSARIF)
+	if err != nil {
+		return err
+	}
+
+	jobs, err := cmd.Flags().GetInt("jobs")
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Processing flag jobs value=%d", jobs)
+
+	if jobs < 1 {
+		return fmt.Errorf("%w: %d", errInvalidJobs, jobs)
+	}
+
+	rules, err := getValidateRules(cmd)
+	if err != nil {
+		return err
+	}
+
+	values, err := getTemplateValues(cmd)
+	if err != nil {
+		return err
 	}
 
 	// read stdin by default
-	var srcPath string
-	if len(args) == 0 {
-		srcPath = os.Stdin.Name()
-	} else {
-		srcPath = args[0]
+	srcPaths := []string{os.Stdin.Name()}
+	if len(args) > 0 {
+		srcPaths, err = expandValidateSources(args)
+		if err != nil {
+			return err
+		}
 	}
 
-	srcFormat, err := getConfFileFormat(srcPath, cmd)
+	if len(srcPaths) == 1 {
+		return runValidateSingle(cmd, outFmt, srcPaths[0], rules, values)
+	}
+
+	results := validateSources(cmd, srcPaths, jobs, rules, values)
+	summary := newValidateSummary(results)
+
+	if err := renderValidateBatch(cmd, outFmt, results, summary); err != nil {
+		return err
+	}
+
+	if summary.Invalid > 0 || summary.Failed > 0 {
+		return errors.Join(conf.ErrConfigValidation, ErrSilent)
+	}
+
+	return nil
+}
+
+// runValidateSingle validates a single source, errors that prevent
+// validation are returned instead of being reported as a result.
+func runValidateSingle(cmd *cobra.Command, outFmt, srcPath string, rules validateRules, values map[string]any) error {
+	res, err := validateSource(cmd, srcPath, rules, values)
 	if err != nil {
 		return err
 	}
 
+	results := []validateResult{res}
+
+	if !res.Valid {
+		// text output keeps going to stderr as it always has
+		w := cmd.OutOrStdout()
+		if outFmt == outputFormatText {
+			w = cmd.OutOrStderr()
+		}
+
+		if errRender := renderValidateResults(w, outFmt, results); errRender != nil {
+			return errRender
+		}
+
+		return errors.Join(conf.ErrConfigValidation, ErrSilent)
+	}
+
+	if outFmt != outputFormatText {
+		return renderValidateResults(cmd.OutOrStdout(), outFmt, results)
+	}
+
+	// warnings from semantic rules are shown but don't fail validation
+	return renderValidateResults(cmd.OutOrStderr(), outFmt, results)
+}
+
+// validateSources validates srcPaths using at most jobs concurrent workers.
+// Results are returned in the same order as srcPaths.
+func validateSources(
+	cmd *cobra.Command,
+	srcPaths []string,
+	jobs int,
+	rules validateRules,
+	values map[string]any,
+) []validateResult {
+	results := make([]validateResult, len(srcPaths))
+	sem := make(chan struct{}, jobs)
+
+	var wg sync.WaitGroup
+
+	for i, srcPath := range srcPaths {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			res, err := validateSource(cmd, srcPath, rules, values)
+			if err != nil {
+				res = newValidateFailure(srcPath, err)
+			}
+
+			results[i] = res
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// validateSource validates the config file at srcPath against the server version
+// in its metadata, or the --aerospike-version flag if it is set. Templates are
+// rendered with the template values.
+func validateSource(
+	cmd *cobra.Command,
+	srcPath string,
+	rules validateRules,
+	values map[string]any,
+) (validateResult, error) {
+	logger.Debugf("Validating %s", srcPath)
+
+	srcFormat, err := getConfFileFormat(srcPath, cmd)
+	if err != nil {
+		return validateResult{}, err
+	}
+
 	logger.Debugf("Processing flag format value=%v", srcFormat)
 
 	fdata, err := os.ReadFile(srcPath)
 	if err != nil {
-		return err
+		return validateResult{}, err
 	}
 
-	version, err := getMetaDataItemOptional(fdata, metaKeyAerospikeVersion)
+	fdata, err = renderConfigTemplate(fdata, values)
 	if err != nil {
-		return errors.Join(errMissingAerospikeVersion, err)
+		return validateResult{}, err
 	}
 
-	// if the Aerospike server version was not in the file
-	// metadata, require that it is passed as an argument
-	if version == "" {
-		err = cmd.MarkFlagRequired("aerospike-version")
-		if err != nil {
-			return err
-		}
+	version, err := getMetaDataItemOptional(fdata, metaKeyAerospikeVersion)
+	if err != nil {
+		return validateResult{}, errors.Join(errMissingAerospikeVersion, err)
 	}
 
 	versionArg, err := cmd.Flags().GetString("aerospike-version")
 	if err != nil {
 		logger.Errorf("Unable to get aerospike-version flag: %v", err)
-		return err
+		return validateResult{}, err
 	}
 
 	// the command line --aerospike-version option overrides
@@ -94,21 +243,208 @@ func runValidateCommand(cmd *cobra.Command, args []string) error {
 		version = versionArg
 	}
 
-	logger.Debugf("Processing flag aerospike-version value=%s", version)
+	// if the Aerospike server version was not in the file
+	// metadata, require that it is passed as an argument
+	if version == "" {
+		return validateResult{}, errMissingAerospikeVersion
+	}
 
-	asconfig, err := asConf.NewASConfigFromBytes(mgmtLibLogger, fdata, srcFormat)
+	logger.Debugf("Processing flag aerospike-version value=%s", version)
 
+	asconfig, err := conf.NewASConfigFromBytes(mgmtLibLogger, fdata, srcFormat)
 	if err != nil {
-		return err
+		return validateResult{}, err
 	}
 
-	verrs, err := conf.NewConfigValidator(asconfig, mgmtLibLogger, version).Validate()
+	verrs, err := newSourceValidator(asconfig, fdata, srcFormat, version, rules.opts, rules.rules...).Validate()
 	// verrs is an empty slice if err is not nil but no
 	// validation errors were found
 	if verrs != nil && len(verrs.Errors) > 0 {
-		cmd.Print(verrs.Error())
-		return errors.Join(conf.ErrConfigValidation, ErrSilent)
+		return newValidateResult(srcPath, version, verrs), nil
+	}
+
+	if err != nil {
+		return validateResult{}, err
+	}
+
+	return newValidateResult(srcPath, version, verrs), nil
+}
+
+// expandValidateSources expands validate arguments into a list of files.
+// Glob patterns are expanded and directories are searched recursively for files
+// with a supported config file extension. Duplicate paths are only returned once.
+func expandValidateSources(args []string) ([]string, error) {
+	var res []string
+
+	seen := map[string]struct{}{}
+	addPath := func(path string) {
+		if _, ok := seen[path]; !ok {
+			seen[path] = struct{}{}
+			res = append(res, path)
+		}
+	}
+
+	for _, arg := range args {
+		matches := []string{arg}
+
+		if strings.ContainsAny(arg, globMetaChars) {
+			var err error
+
+			matches, err = filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", errInvalidGlob, arg, err)
+			}
+
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("%w: %s", errNoFilesMatched, arg)
+			}
+		}
+
+		for _, match := range matches {
+			stat, err := os.Stat(match)
+			if err != nil || !stat.IsDir() {
+				// files that can't be read are reported by validateSource
+				addPath(match)
+				continue
+			}
+
+			files, err := findConfigFiles(match)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(files) == 0 {
+				return nil, fmt.Errorf("%w: %s", errNoFilesMatched, match)
+			}
+
+			for _, file := range files {
+				addPath(file)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// findConfigFiles returns the files under dir with a supported config file extension.
+func findConfigFiles(dir string) ([]string, error) {
+	var res []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		ext := strings.TrimPrefix(filepath.Ext(path), ".")
+		if _, errFmt := ParseFmtString(ext); errFmt == nil {
+			res = append(res, path)
+		}
+
+		return nil
+	})
+
+	return res, err
+}
+
+// validateRules are the semantic rules, and the options they
+// use, that are checked for every validated source.
+type validateRules struct {
+	opts  conf.RuleOptions
+	rules []conf.Rule
+}
+
+// getValidateRules returns the default semantic rules and the
+// rules of the --policy file if one is provided.
+func getValidateRules(cmd *cobra.Command) (validateRules, error) {
+	opts, err := getRuleOptions(cmd)
+	if err != nil {
+		return validateRules{}, err
+	}
+
+	res := validateRules{opts: opts, rules: conf.DefaultRules()}
+
+	policyPath, err := cmd.Flags().GetString("policy")
+	if err != nil {
+		return validateRules{}, err
+	}
+
+	if policyPath == "" {
+		return res, nil
+	}
+
+	logger.Debugf("Processing flag policy value=%s", policyPath)
+
+	data, err := os.ReadFile(policyPath)
+	if err != nil {
+		return validateRules{}, err
+	}
+
+	policyRules, err := conf.LoadPolicy(data)
+	if err != nil {
+		return validateRules{}, fmt.Errorf("%s: %w", policyPath, err)
+	}
+
+	res.rules = append(res.rules, policyRules...)
+
+	return res, nil
+}
+
+// getRuleOptions reads the deployment facts used by semantic rules from the
+// validate flags. Commands without the flags use the zero options.
+func getRuleOptions(cmd *cobra.Command) (conf.RuleOptions, error) {
+	var opts conf.RuleOptions
+
+	if cmd.Flags().Lookup("rack-count") != nil {
+		rackCount, err := cmd.Flags().GetInt("rack-count")
+		if err != nil {
+			return opts, err
+		}
+
+		if rackCount < 0 {
+			return opts, fmt.Errorf("%w: %d", errInvalidRackCount, rackCount)
+		}
+
+		opts.RackCount = rackCount
+	}
+
+	if cmd.Flags().Lookup("host-memory") != nil {
+		hostMemory, err := cmd.Flags().GetString("host-memory")
+		if err != nil {
+			return opts, err
+		}
+
+		opts.HostMemory, err = parseSize(hostMemory)
+		if err != nil {
+			return opts, fmt.Errorf("%w: %s", errInvalidHostMemory, hostMemory)
+		}
+	}
+
+	return opts, nil
+}
+
+// newSourceValidator returns a config validator for asconfig that checks rules
+// and reports the line and column of validation errors in src. Positions are
+// best effort, if src can't be indexed the errors are reported without them.
+func newSourceValidator(
+	asconfig conf.ConfHandler,
+	src []byte,
+	srcFormat asConf.Format,
+	version string,
+	opts conf.RuleOptions,
+	rules ...conf.Rule,
+) *conf.ConfigValidator {
+	validator := conf.NewConfigValidator(asconfig, mgmtLibLogger, version).
+		WithRules(opts, rules...)
+
+	positions, err := conf.NewSourcePositions(src, srcFormat)
+	if err != nil {
+		logger.Debugf("Unable to determine source positions: %v", err)
+		return validator
 	}
 
-	return err
+	return validator.WithSourcePositions(positions)
 }
@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aerospike/asconfig/conf"
+)
+
+const (
+	sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion   = "2.1.0"
+	sarifToolName  = "asconfig"
+	sarifToolURI   = "https://github.com/aerospike/asconfig"
+	sarifLevelErr  = "error"
+	sarifLevelWarn = "warning"
+	sarifLevelNote = "note"
+)
+
+// validateResult is the outcome of validating a single configuration source.
+type validateResult struct {
+	Source  string         `json:"source"`
+	Version string         `json:"aerospike-version"`
+	Valid   bool           `json:"valid"`
+	Errors  conf.VErrSlice `json:"errors"`
+	// Failure describes an error that prevented the source from being validated.
+	Failure string `json:"failure,omitempty"`
+}
+
+// newValidateResult builds a validateResult from the validation errors of source.
+func newValidateResult(source, version string, verrs *conf.ValidationErrors) validateResult {
+	res := validateResult{
+		Source:  source,
+		Version: version,
+		Valid:   true,
+		Errors:  conf.VErrSlice{},
+	}
+
+	if verrs != nil {
+		// rule warnings are reported without making the source invalid
+		res.Valid = !verrs.HasErrors()
+		res.Errors = verrs.Reportable()
+	}
+
+	return res
+}
+
+// newValidateFailure builds the result of a source that could not be validated.
+func newValidateFailure(source string, err error) validateResult {
+	return validateResult{
+		Source:  source,
+		Errors:  conf.VErrSlice{},
+		Failure: err.Error(),
+	}
+}
+
+// validateSummary aggregates the results of validating many sources.
+type validateSummary struct {
+	Files   int `json:"files"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+	Failed  int `json:"failed"`
+}
+
+// newValidateSummary counts the valid, invalid and failed results.
+func newValidateSummary(results []validateResult) validateSummary {
+	summary := validateSummary{Files: len(results)}
+
+	for _, res := range results {
+		switch {
+		case res.Failure != "":
+			summary.Failed++
+		case res.Valid:
+			summary.Valid++
+		default:
+			summary.Invalid++
+		}
+	}
+
+	return summary
+}
+
+// validateReport is the machine readable document written by validate.
+type validateReport struct {
+	Results []validateResult `json:"results"`
+	Summary validateSummary  `json:"summary"`
+}
+
+// renderValidateResults writes results to w in the requested output format.
+func renderValidateResults(w io.Writer, outFmt string, results []validateResult) error {
+	switch outFmt {
+	case outputFormatJSON:
+		return renderStructured(w, outputFormatJSON, validateReport{Results: results, Summary: newValidateSummary(results)})
+	case outputFormatSARIF:
+		return renderStructured(w, outputFormatJSON, newSarifLog(results))
+	default:
+		for _, res := range results {
+			if len(res.Errors) == 0 {
+				continue
+			}
+
+			verrs := conf.ValidationErrors{Errors: res.Errors}
+			if _, err := fmt.Fprint(w, verrs.Error()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// renderValidateBatch writes the result of each source followed by a summary.
+// Machine readable formats include the summary in their single document.
+func renderValidateBatch(cmd *cobra.Command, outFmt string, results []validateResult, summary validateSummary) error {
+	if outFmt != outputFormatText {
+		return renderValidateResults(cmd.OutOrStdout(), outFmt, results)
+	}
+
+	w := cmd.OutOrStderr()
+
+	for _, res := range results {
+		var err error
+
+		switch {
+		case res.Failure != "":
+			_, err = fmt.Fprintf(w, "%s: failed: %s\n", res.Source, res.Failure)
+		case res.Valid && len(res.Errors) == 0:
+			_, err = fmt.Fprintf(w, "%s: valid\n", res.Source)
+		case res.Valid:
+			_, err = fmt.Fprintf(w, "%s: valid with warnings\n", res.Source)
+			if err == nil {
+				err = renderValidateResults(w, outFmt, []validateResult{res})
+			}
+		default:
+			_, err = fmt.Fprintf(w, "%s: invalid\n", res.Source)
+			if err == nil {
+				err = renderValidateResults(w, outFmt, []validateResult{res})
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "\nValidated %d files: %d valid, %d invalid, %d failed\n",
+		summary.Files, summary.Valid, summary.Invalid, summary.Failed)
+
+	return err
+}
+
+// The sarif types below are the subset of the SARIF 2.1.0 format
+// needed to report configuration validation errors.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations"`
+	Properties map[string]any  `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// newSarifLog converts validation results to a single run SARIF log.
+func newSarifLog(results []validateResult) sarifLog {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           sarifToolName,
+				Version:        VERSION,
+				InformationURI: sarifToolURI,
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	ruleIDs := map[string]struct{}{}
+	invocation := sarifInvocation{ExecutionSuccessful: true}
+
+	for _, res := range results {
+		// sources that could not be validated are tool execution problems
+		// rather than results
+		if res.Failure != "" {
+			invocation.ExecutionSuccessful = false
+			invocation.ToolExecutionNotifications = append(
+				invocation.ToolExecutionNotifications,
+				sarifNotification{
+					Level:   sarifLevelErr,
+					Message: sarifMessage{Text: res.Failure},
+					Locations: []sarifLocation{{
+						PhysicalLocation: sarifPhysicalLocation{
+							ArtifactLocation: sarifArtifactLocation{URI: res.Source},
+						},
+					}},
+				},
+			)
+
+			continue
+		}
+
+		for _, verr := range res.Errors {
+			ruleIDs[sarifRuleID(verr)] = struct{}{}
+			run.Results = append(run.Results, newSarifResult(res.Source, verr))
+		}
+	}
+
+	ids := make([]string, 0, len(ruleIDs))
+	for id := range ruleIDs {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	run.Invocations = []sarifInvocation{invocation}
+
+	for _, id := range ids {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id})
+	}
+
+	return sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+}
+
+// newSarifResult converts a single validation error found in source to a SARIF result.
+func newSarifResult(source string, verr conf.ValidationError) sarifResult {
+	res := sarifResult{
+		RuleID:  sarifRuleID(verr),
+		Level:   sarifLevel(verr.Severity),
+		Message: sarifMessage{Text: verr.Description},
+		Locations: []sarifLocation{
+			{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: source},
+				},
+				LogicalLocations: []sarifLogicalLocation{
+					{FullyQualifiedName: verr.Context},
+				},
+			},
+		},
+		Properties: map[string]any{},
+	}
+
+	if verr.Line > 0 {
+		res.Locations[0].PhysicalLocation.Region = &sarifRegion{
+			StartLine:   verr.Line,
+			StartColumn: verr.Column,
+		}
+	}
+
+	if verr.Field != "" {
+		res.Properties["field"] = verr.Field
+	}
+
+	if verr.Version != "" {
+		res.Properties["aerospike-version"] = verr.Version
+	}
+
+	return res
+}
+
+// sarifRuleID returns the id of the semantic rule or schema error type that produced verr.
+func sarifRuleID(verr conf.ValidationError) string {
+	if verr.RuleID != "" {
+		return verr.RuleID
+	}
+
+	return verr.ErrType
+}
+
+// sarifLevel converts a validation error severity to a SARIF result level.
+func sarifLevel(severity conf.Severity) string {
+	switch severity {
+	case conf.SeverityWarning:
+		return sarifLevelWarn
+	case conf.SeverityInfo:
+		return sarifLevelNote
+	case conf.SeverityError:
+		return sarifLevelErr
+	default:
+		return sarifLevelErr
+	}
+}
@@ -0,0 +1,175 @@
+//go:build unit
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aerospike/aerospike-management-lib/asconfig"
+
+	"github.com/aerospike/asconfig/conf"
+)
+
+func testValidationErrors() *conf.ValidationErrors {
+	return &conf.ValidationErrors{
+		Errors: conf.VErrSlice{
+			{
+				ValidationErr: asconfig.ValidationErr{
+					Context:     "namespaces.test",
+					Field:       "namespaces.test.replication-factor",
+					Description: "Must be less than or equal to 256",
+					ErrType:     "number_lte",
+				},
+				Version: "7.0.0",
+				Line:    12,
+				Column:  5,
+			},
+			{
+				ValidationErr: asconfig.ValidationErr{
+					Context:     "namespaces.test.storage-engine",
+					Description: "Must validate one and only one schema (oneOf)",
+					ErrType:     "number_one_of",
+				},
+				Version: "7.0.0",
+			},
+		},
+	}
+}
+
+func TestNewValidateResult(t *testing.T) {
+	res := newValidateResult("aerospike.conf", "7.0.0", testValidationErrors())
+	if res.Valid {
+		t.Errorf("newValidateResult() Valid = true, want false")
+	}
+
+	if len(res.Errors) != 1 {
+		t.Fatalf("newValidateResult() returned %d errors, want 1 (number_one_of filtered)", len(res.Errors))
+	}
+
+	warnings := &conf.ValidationErrors{Errors: conf.VErrSlice{{
+		ValidationErr: asconfig.ValidationErr{
+			Context:     "namespaces.test.replication-factor",
+			Description: "replication-factor 3 is larger than the rack count 2",
+			ErrType:     "rule_violation",
+		},
+		RuleID:   "replication-factor-exceeds-racks",
+		Severity: conf.SeverityWarning,
+	}}}
+
+	res = newValidateResult("aerospike.conf", "7.0.0", warnings)
+	if !res.Valid || len(res.Errors) != 1 {
+		t.Errorf("newValidateResult() = %+v, want valid result with 1 warning", res)
+	}
+
+	sarifRes := newSarifResult(res.Source, res.Errors[0])
+	if sarifRes.RuleID != "replication-factor-exceeds-racks" || sarifRes.Level != sarifLevelWarn {
+		t.Errorf("newSarifResult() = %+v, want rule id and warning level", sarifRes)
+	}
+
+	res = newValidateResult("aerospike.conf", "7.0.0", &conf.ValidationErrors{})
+	if !res.Valid || res.Errors == nil {
+		t.Errorf("newValidateResult() = %+v, want valid result with empty errors", res)
+	}
+}
+
+func TestRenderValidateResults(t *testing.T) {
+	results := []validateResult{newValidateResult("aerospike.conf", "7.0.0", testValidationErrors())}
+
+	testCases := []struct {
+		name   string
+		outFmt string
+		check  func(t *testing.T, out string)
+	}{
+		{
+			name:   "text",
+			outFmt: outputFormatText,
+			check: func(t *testing.T, out string) {
+				if !strings.Contains(out, "context: namespaces.test (line 12, column 5)\n") {
+					t.Errorf("text output missing context line: %q", out)
+				}
+			},
+		},
+		{
+			name:   "json",
+			outFmt: outputFormatJSON,
+			check: func(t *testing.T, out string) {
+				var report struct {
+					Results []struct {
+						Source  string           `json:"source"`
+						Version string           `json:"aerospike-version"`
+						Valid   bool             `json:"valid"`
+						Errors  []map[string]any `json:"errors"`
+					} `json:"results"`
+				}
+				if err := json.Unmarshal([]byte(out), &report); err != nil {
+					t.Fatalf("invalid json output: %v", err)
+				}
+				if len(report.Results) != 1 || len(report.Results[0].Errors) != 1 {
+					t.Fatalf("unexpected json report: %s", out)
+				}
+				verr := report.Results[0].Errors[0]
+				if verr["context"] != "namespaces.test" || verr["error-type"] != "number_lte" ||
+					verr["version"] != "7.0.0" {
+					t.Errorf("unexpected json error record: %v", verr)
+				}
+			},
+		},
+		{
+			name:   "sarif",
+			outFmt: outputFormatSARIF,
+			check: func(t *testing.T, out string) {
+				var log sarifLog
+				if err := json.Unmarshal([]byte(out), &log); err != nil {
+					t.Fatalf("invalid sarif output: %v", err)
+				}
+				if log.Version != sarifVersion || len(log.Runs) != 1 {
+					t.Fatalf("unexpected sarif log: %s", out)
+				}
+				run := log.Runs[0]
+				if len(run.Results) != 1 || run.Results[0].RuleID != "number_lte" {
+					t.Errorf("unexpected sarif results: %+v", run.Results)
+				}
+				if len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].ID != "number_lte" {
+					t.Errorf("unexpected sarif rules: %+v", run.Tool.Driver.Rules)
+				}
+				loc := run.Results[0].Locations[0]
+				if loc.PhysicalLocation.ArtifactLocation.URI != "aerospike.conf" ||
+					loc.PhysicalLocation.Region == nil || loc.PhysicalLocation.Region.StartLine != 12 {
+					t.Errorf("unexpected sarif location: %+v", loc)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := renderValidateResults(&buf, tc.outFmt, results); err != nil {
+				t.Fatalf("renderValidateResults() error = %v", err)
+			}
+			tc.check(t, buf.String())
+		})
+	}
+}
+
+func TestNewValidateSummary(t *testing.T) {
+	results := []validateResult{
+		newValidateResult("valid.conf", "7.0.0", &conf.ValidationErrors{}),
+		newValidateResult("invalid.conf", "7.0.0", testValidationErrors()),
+		newValidateFailure("missing.conf", errFileNotExist),
+	}
+
+	want := validateSummary{Files: 3, Valid: 1, Invalid: 1, Failed: 1}
+	if got := newValidateSummary(results); got != want {
+		t.Errorf("newValidateSummary() = %+v, want %+v", got, want)
+	}
+
+	log := newSarifLog(results)
+	invocation := log.Runs[0].Invocations[0]
+	if invocation.ExecutionSuccessful || len(invocation.ToolExecutionNotifications) != 1 {
+		t.Errorf("newSarifLog() invocation = %+v, want one failed source notification", invocation)
+	}
+}
@@ -3,6 +3,14 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -59,6 +67,21 @@ var testValidateArgs = []runTestValidate{
 		arguments:   []string{},
 		expectError: true,
 	},
+	{
+		flags:       []string{"--aerospike-version", "7.0.0", "--output-format", "bad_fmt"},
+		arguments:   []string{"../testdata/cases/server70/server70.yaml"},
+		expectError: true,
+	},
+	{
+		flags:       []string{"--aerospike-version", "7.0.0", "--output-format", "json"},
+		arguments:   []string{"../testdata/cases/server70/server70.yaml"},
+		expectError: false,
+	},
+	{
+		flags:       []string{"--aerospike-version", "7.0.0", "--output-format", "sarif"},
+		arguments:   []string{"../testdata/cases/server70/server70.yaml"},
+		expectError: false,
+	},
 }
 
 func TestRunEValidate(t *testing.T) {
@@ -69,6 +92,8 @@ func TestRunEValidate(t *testing.T) {
 	for i, test := range testValidateArgs {
 		// Create a fresh command instance for each test case
 		cmd := newValidateCmd()
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
 
 		cmd.ParseFlags(test.flags)
 		err := cmd.RunE(cmd, test.arguments)
@@ -77,3 +102,200 @@ func TestRunEValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestExpandValidateSources(t *testing.T) {
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "nested")
+
+	if err := os.Mkdir(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"a.conf", "b.yaml", "notes.txt", filepath.Join("nested", "c.yml")} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte{}, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "directory is searched recursively for config files",
+			args: []string{dir},
+			want: []string{
+				filepath.Join(dir, "a.conf"),
+				filepath.Join(dir, "b.yaml"),
+				filepath.Join(nested, "c.yml"),
+			},
+		},
+		{
+			name: "glob and duplicate file",
+			args: []string{filepath.Join(dir, "*.conf"), filepath.Join(dir, "a.conf")},
+			want: []string{filepath.Join(dir, "a.conf")},
+		},
+		{
+			name: "explicit files are kept regardless of extension",
+			args: []string{filepath.Join(dir, "notes.txt"), "missing.conf"},
+			want: []string{filepath.Join(dir, "notes.txt"), "missing.conf"},
+		},
+		{
+			name:    "glob without matches",
+			args:    []string{filepath.Join(dir, "*.json")},
+			wantErr: errNoFilesMatched,
+		},
+		{
+			name:    "invalid glob",
+			args:    []string{"[bad"},
+			wantErr: errInvalidGlob,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := expandValidateSources(tc.args)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expandValidateSources() error = %v, want %v", err, tc.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expandValidateSources() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunEValidateBatch(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	testCases := []struct {
+		flags     []string
+		arguments []string
+		files     int
+		failed    int
+	}{
+		{
+			flags:     []string{"--aerospike-version", "7.0.0", "--jobs", "2"},
+			arguments: []string{"../testdata/cases/server64/server64.yaml", "./fake_file.yaml", "./fake_file.conf"},
+			files:     3,
+			failed:    2,
+		},
+	}
+
+	for i, tc := range testCases {
+		cmd := newValidateCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+
+		cmd.ParseFlags(append(tc.flags, "--output-format", "json"))
+		if err := cmd.RunE(cmd, tc.arguments); err == nil {
+			t.Fatalf("case: %d, expected an error for invalid files", i)
+		}
+
+		var report validateReport
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatalf("case: %d, invalid json output: %v", i, err)
+		}
+
+		if report.Summary.Files != tc.files || report.Summary.Failed != tc.failed {
+			t.Errorf("case: %d, summary = %+v, want %d files and %d failed", i, report.Summary, tc.files, tc.failed)
+		}
+
+		for j, res := range report.Results {
+			if res.Source != tc.arguments[j] {
+				t.Errorf("case: %d, result %d source = %s, want %s", i, j, res.Source, tc.arguments[j])
+			}
+		}
+	}
+
+	cmd := newValidateCmd()
+	cmd.ParseFlags([]string{"--jobs", "0"})
+	if err := cmd.RunE(cmd, []string{"a.conf", "b.conf"}); !errors.Is(err, errInvalidJobs) {
+		t.Errorf("expected errInvalidJobs, got %v", err)
+	}
+}
+
+func TestRunEValidatePolicy(t *testing.T) {
+	if err := InitializeGlobals(); err != nil {
+		t.Fatalf("Failed to initialize globals for testing: %v", err)
+	}
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+
+		return path
+	}
+
+	passing := writeFile("passing.yaml", `
+rules:
+  - id: namespace-names
+    path: namespaces[*].name
+    pattern: ^ns[0-9]+$
+`)
+	failing := writeFile("failing.yaml", `
+rules:
+  - id: proto-fd-max
+    path: service.proto-fd-max
+    min: 100000
+`)
+	warning := writeFile("warning.yaml", `
+rules:
+  - id: proto-fd-max
+    severity: warning
+    path: service.proto-fd-max
+    min: 100000
+`)
+	invalid := writeFile("invalid.yaml", `
+rules:
+  - id: no-path
+    min: 1
+`)
+
+	// a small config that is valid against the schema so only the policy can fail it
+	config := writeFile("aerospike.conf", "service {\n\tproto-fd-max 15000\n}\n"+
+		"logging {\n\tconsole {\n\t\tcontext any info\n\t}\n}\n"+
+		"namespace ns1 {\n\treplication-factor 2\n\tstorage-engine memory\n}\n")
+
+	tests := []struct {
+		name        string
+		policy      string
+		expectError bool
+		wantOutput  string
+	}{
+		{name: "passing", policy: passing},
+		{name: "failing", policy: failing, expectError: true, wantOutput: "rule-id: proto-fd-max"},
+		{name: "warning", policy: warning, wantOutput: "rule-id: proto-fd-max"},
+		{name: "invalid", policy: invalid, expectError: true},
+		{name: "missing", policy: filepath.Join(dir, "missing.yaml"), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newValidateCmd()
+
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetErr(&out)
+
+			cmd.ParseFlags([]string{"--aerospike-version", "7.0.0", "--policy", tt.policy})
+			err := cmd.RunE(cmd, []string{config})
+			if tt.expectError == (err == nil) {
+				t.Fatalf("expectError: %v does not match err: %v", tt.expectError, err)
+			}
+
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("RunE() output = %s, want it to contain %q", out.String(), tt.wantOutput)
+			}
+		})
+	}
+}
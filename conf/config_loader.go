@@ -0,0 +1,56 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aerospike/asconfig/conf/metadata"
+)
+
+// JSON is the json config format. It has the structure of the yaml format,
+// asconfig metadata is carried in the reserved metadata.JSONField.
+const JSON asConf.Format = "json"
+
+var ErrInvalidJSON = errors.New("config is not a json object")
+
+// NewASConfigFromBytes loads a config in any of the registered formats.
+func NewASConfigFromBytes(log logr.Logger, src []byte, format asConf.Format) (*asConf.AsConfig, error) {
+	codec, ok := formats[format]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", asConf.ErrInvalidFormat, format)
+	}
+
+	return codec.Unmarshal(log, src)
+}
+
+// newASConfigFromMap loads a config decoded from a format with the structure
+// of the yaml format. It is loaded as yaml so that it is normalized the same way.
+func newASConfigFromMap(log logr.Logger, data map[string]any) (*asConf.AsConfig, error) {
+	ysrc, err := yaml.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return asConf.NewASConfigFromBytes(log, ysrc, asConf.YAML)
+}
+
+func unmarshalJSON(log logr.Logger, src []byte) (*asConf.AsConfig, error) {
+	if !json.Valid(src) {
+		return nil, ErrInvalidJSON
+	}
+
+	// json is a subset of yaml, parsing it as yaml keeps integers integers
+	var data map[string]any
+	if err := yaml.Unmarshal(src, &data); err != nil {
+		return nil, errors.Join(ErrInvalidJSON, err)
+	}
+
+	delete(data, metadata.JSONField)
+
+	return newASConfigFromMap(log, data)
+}
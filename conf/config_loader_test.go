@@ -0,0 +1,60 @@
+//go:build unit
+
+package conf
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/go-logr/logr"
+)
+
+func TestNewASConfigFromBytesJSON(t *testing.T) {
+	src := []byte(`{
+  "_asconfig": {"aerospike-server-version": "7.0.0"},
+  "service": {"proto-fd-max": 15000},
+  "namespaces": [{"name": "test", "replication-factor": 2, "storage-engine": {"type": "memory"}}]
+}`)
+
+	got, err := NewASConfigFromBytes(logr.Discard(), src, JSON)
+	if err != nil {
+		t.Fatalf("NewASConfigFromBytes() error = %v", err)
+	}
+
+	want, err := asConf.NewASConfigFromBytes(logr.Discard(), []byte(`
+service:
+  proto-fd-max: 15000
+namespaces:
+  - name: test
+    replication-factor: 2
+    storage-engine:
+      type: memory
+`), asConf.YAML)
+	if err != nil {
+		t.Fatalf("NewASConfigFromBytes() of yaml error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got.ToMap(), want.ToMap()) {
+		t.Errorf("NewASConfigFromBytes() = %v, want %v", got.ToMap(), want.ToMap())
+	}
+
+	text, err := NewConfigMarshaller(got, JSON).MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() error = %v", err)
+	}
+
+	roundTrip, err := NewASConfigFromBytes(logr.Discard(), text, JSON)
+	if err != nil {
+		t.Fatalf("NewASConfigFromBytes() of marshalled json error = %v", err)
+	}
+
+	if !reflect.DeepEqual(roundTrip.ToMap(), got.ToMap()) {
+		t.Errorf("json round trip = %v, want %v", roundTrip.ToMap(), got.ToMap())
+	}
+
+	if _, err := NewASConfigFromBytes(logr.Discard(), []byte("service:\n  proto-fd-max: 1\n"), JSON); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("NewASConfigFromBytes() of yaml as json error = %v, want %v", err, ErrInvalidJSON)
+	}
+}
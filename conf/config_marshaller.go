@@ -4,13 +4,14 @@ import (
 	"fmt"
 
 	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
-	"gopkg.in/yaml.v3"
 )
 
 type ConfigMarshaller struct {
 	ConfHandler
 
 	Format asConf.Format
+	// Layout is the layout of the source that is kept when it is set.
+	Layout *SourceLayout
 }
 
 func NewConfigMarshaller(conf ConfHandler, format asConf.Format) ConfigMarshaller {
@@ -20,20 +21,23 @@ func NewConfigMarshaller(conf ConfHandler, format asConf.Format) ConfigMarshalle
 	}
 }
 
+// WithSourceLayout returns a marshaller that keeps the order and the comments
+// of the source layout. Layouts are kept in the yaml and conf formats.
+func (cm ConfigMarshaller) WithSourceLayout(layout *SourceLayout) ConfigMarshaller {
+	cm.Layout = layout
+	return cm
+}
+
+// MarshalText writes the config in the registered format of the marshaller.
 func (cm ConfigMarshaller) MarshalText() ([]byte, error) {
-	var text []byte
-	var err error
-	switch cm.Format {
-	case asConf.AeroConfig:
-		text = []byte(cm.ToConfFile())
-	case asConf.YAML:
-		m := cm.ToMap()
-		text, err = yaml.Marshal(m)
-	case asConf.Invalid:
-		err = fmt.Errorf("%w %s", asConf.ErrInvalidFormat, cm.Format)
-	default:
-		err = fmt.Errorf("%w %s", asConf.ErrInvalidFormat, cm.Format)
+	if cm.Layout != nil {
+		return marshalWithLayout(cm.ConfHandler, cm.Format, cm.Layout)
+	}
+
+	codec, ok := formats[cm.Format]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", asConf.ErrInvalidFormat, cm.Format)
 	}
 
-	return text, err
+	return codec.Marshal(cm.ConfHandler)
 }
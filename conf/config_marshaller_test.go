@@ -45,6 +45,20 @@ func Test_asconf_MarshalText(t *testing.T) {
 			wantErr:  false,
 			wantText: []byte("namespaces: ns1\n"),
 		},
+		{
+			name: "valid json format",
+			fields: fields{
+				cfg: &mockCFG{
+					confMap: &asConf.Conf{
+						"namespaces": "ns1",
+					},
+					confText: "",
+				},
+				outFmt: JSON,
+			},
+			wantErr:  false,
+			wantText: []byte("{\n  \"namespaces\": \"ns1\"\n}\n"),
+		},
 		{
 			name: "invalid format",
 			fields: fields{
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -27,6 +28,9 @@ type ConfigValidator struct {
 
 	mgmtLogger logr.Logger
 	version    string
+	positions  SourcePositions
+	rules      []Rule
+	ruleOpts   RuleOptions
 }
 
 func NewConfigValidator(confHandler ConfHandler, mgmtLogger logr.Logger, version string) *ConfigValidator {
@@ -37,7 +41,25 @@ func NewConfigValidator(confHandler ConfHandler, mgmtLogger logr.Logger, version
 	}
 }
 
-// Validate validates the parsed configuration against the schema for the given versions.
+// WithSourcePositions sets the source positions used to annotate
+// validation errors with the line and column of the offending key or section.
+func (cv *ConfigValidator) WithSourcePositions(positions SourcePositions) *ConfigValidator {
+	cv.positions = positions
+	return cv
+}
+
+// WithRules sets the semantic rules checked after schema validation.
+// opts provides deployment facts that the rules may need.
+func (cv *ConfigValidator) WithRules(opts RuleOptions, rules ...Rule) *ConfigValidator {
+	cv.rules = rules
+	cv.ruleOpts = opts
+
+	return cv
+}
+
+// Validate validates the parsed configuration against the schema for the given versions
+// and then checks any semantic rules. Rule violations with a severity lower than
+// SeverityError are returned in ValidationErrors without failing validation.
 // ValidationErrors is not nil if any errors occur during validation.
 func (cv *ConfigValidator) Validate() (*ValidationErrors, error) {
 	valid, tempVerrs, err := cv.IsValid(cv.mgmtLogger, cv.version)
@@ -47,44 +69,113 @@ func (cv *ConfigValidator) Validate() (*ValidationErrors, error) {
 	for _, v := range tempVerrs {
 		verr := ValidationError{
 			ValidationErr: *v,
+			Version:       cv.version,
+			Severity:      SeverityError,
 		}
 		verrs.Errors = append(verrs.Errors, verr)
 	}
 
-	if !valid || err != nil || len(verrs.Errors) > 0 {
-		config := cv.ToMap()
+	schemaInvalid := !valid || err != nil || len(verrs.Errors) > 0
+	if !schemaInvalid && len(cv.rules) == 0 {
+		return &ValidationErrors{}, nil
+	}
 
-		jsonConfigStr, errMarshal := json.Marshal(config)
-		if errMarshal != nil {
-			return nil, errMarshal
-		}
+	jsonConfig, errJSON := cv.jsonConfig()
+	if errJSON != nil {
+		return nil, errJSON
+	}
 
-		jsonConfig := map[string]any{}
-		err = json.Unmarshal(jsonConfigStr, &jsonConfig)
+	if schemaInvalid {
+		cv.resolveContexts(jsonConfig, verrs.Errors)
+	}
 
-		// check the context of each error and use that context to get the name
-		// of the field that is causing the error from the json config
-		for i, verr := range verrs.Errors {
-			context, _ := strings.CutPrefix(verr.Context, "(root).")
+	ruleErrs := checkRules(cv.rules, jsonConfig, cv.version, cv.ruleOpts)
+	for i := range ruleErrs {
+		cv.setPosition(&ruleErrs[i])
+	}
 
-			context, errContext := jsonToConfigContext(jsonConfig, context)
-			if errContext != nil {
-				// if we can't associate the error with its
-				// corresponding field, just use the current context
-				continue
-			}
+	verrs.Errors = append(verrs.Errors, ruleErrs...)
 
-			verrs.Errors[i].Context = context
+	if schemaInvalid || verrs.HasErrors() {
+		return &verrs, errors.Join(ErrConfigValidation, err)
+	}
+
+	return &verrs, nil
+}
+
+// jsonConfig returns the configuration as generic json types.
+func (cv *ConfigValidator) jsonConfig() (map[string]any, error) {
+	jsonConfigStr, err := json.Marshal(cv.ToMap())
+	if err != nil {
+		return nil, err
+	}
+
+	jsonConfig := map[string]any{}
+	err = json.Unmarshal(jsonConfigStr, &jsonConfig)
+
+	return jsonConfig, err
+}
+
+// resolveContexts replaces the list indexes in the context of each schema error
+// with the name of the list item that is causing the error.
+func (cv *ConfigValidator) resolveContexts(jsonConfig map[string]any, errs VErrSlice) {
+	for i, verr := range errs {
+		context, _ := strings.CutPrefix(verr.Context, "(root).")
+
+		context, errContext := jsonToConfigContext(jsonConfig, context)
+		if errContext != nil {
+			// if we can't associate the error with its
+			// corresponding field, just use the current context
+			continue
 		}
 
-		return &verrs, errors.Join(ErrConfigValidation, err)
+		errs[i].Context = context
+		cv.setPosition(&errs[i])
+
+		// the field uses the same indexed format as the context
+		if field, errField := jsonToConfigContext(jsonConfig, verr.Field); errField == nil {
+			errs[i].Field = field
+		}
 	}
+}
 
-	return &ValidationErrors{}, nil
+// setPosition sets the source line and column of verr if they are known.
+func (cv *ConfigValidator) setPosition(verr *ValidationError) {
+	if pos, ok := cv.positions.Lookup(verr.Context); ok {
+		verr.Line = pos.Line
+		verr.Column = pos.Column
+	}
 }
 
+// errTypeNumberOneOf is the error type of "Must validate one and only one schema" errors.
+const errTypeNumberOneOf = "number_one_of"
+
 type ValidationError struct {
 	asconfig.ValidationErr
+
+	// Version is the Aerospike server version whose schema produced the error.
+	Version string
+	// Line and Column locate the error's context in the source, they are 0 when unknown.
+	Line   int
+	Column int
+	// RuleID is the ID of the semantic rule that produced the error, it is empty for schema errors.
+	RuleID string
+	// Severity is SeverityError for all schema errors.
+	Severity Severity
+}
+
+// validationErrorRecord is the machine readable form of a ValidationError.
+type validationErrorRecord struct {
+	Context     string `json:"context"`
+	Field       string `json:"field,omitempty"`
+	Description string `json:"description"`
+	ErrType     string `json:"error-type"`
+	Value       any    `json:"value,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	Column      int    `json:"column,omitempty"`
+	RuleID      string `json:"rule-id,omitempty"`
+	Severity    string `json:"severity,omitempty"`
 }
 
 type VErrSlice []ValidationError
@@ -96,15 +187,70 @@ func (a VErrSlice) Less(i, j int) bool { return a[i].Error() < a[j].Error() }
 // Error outputs a human readable string of validation error details.
 // error is not nil if validation, or any other type of error occurs.
 func (o *ValidationError) Error() string {
+	if o.RuleID != "" {
+		verrTemplate := "description: %s, rule-id: %s, severity: %s"
+		return fmt.Sprintf(verrTemplate, o.Description, o.RuleID, o.Severity)
+	}
+
 	verrTemplate := "description: %s, error-type: %s"
+
 	return fmt.Sprintf(verrTemplate, o.Description, o.ErrType)
 }
 
+// MarshalJSON outputs the validation error as a flat record
+// using the same key names as the human readable output.
+func (o ValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(validationErrorRecord{
+		Context:     o.Context,
+		Field:       o.Field,
+		Description: o.Description,
+		ErrType:     o.ErrType,
+		Value:       o.Value,
+		Version:     o.Version,
+		Line:        o.Line,
+		Column:      o.Column,
+		RuleID:      o.RuleID,
+		Severity:    string(o.Severity),
+	})
+}
+
 //nolint:errname // TODO: fix this
 type ValidationErrors struct {
 	Errors VErrSlice
 }
 
+// HasErrors reports whether any validation error makes the configuration invalid.
+// Errors without a severity are treated as SeverityError.
+func (o ValidationErrors) HasErrors() bool {
+	for _, err := range o.Errors {
+		if err.Severity == SeverityError || err.Severity == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reportable returns the sorted validation errors that are worth showing to a user.
+// "Must validate one and only one schema" errors are filtered out, like in Error.
+// The errors of o are left in their order.
+func (o ValidationErrors) Reportable() VErrSlice {
+	res := VErrSlice{}
+
+	errs := slices.Clone(o.Errors)
+	sort.Sort(errs)
+
+	for _, err := range errs {
+		if err.ErrType == errTypeNumberOneOf {
+			continue
+		}
+
+		res = append(res, err)
+	}
+
+	return res
+}
+
 func (o ValidationErrors) Error() string {
 	errorsByContext := map[string]VErrSlice{}
 
@@ -124,14 +270,18 @@ func (o ValidationErrors) Error() string {
 	errString := ""
 
 	for _, ctx := range contexts {
-		errString += fmt.Sprintf("context: %s\n", ctx)
-
 		errList := errorsByContext[ctx]
+
+		if pos := errList[0]; pos.Line > 0 {
+			errString += fmt.Sprintf("context: %s (line %d, column %d)\n", ctx, pos.Line, pos.Column)
+		} else {
+			errString += fmt.Sprintf("context: %s\n", ctx)
+		}
 		for _, err := range errList {
 			// filter "Must validate one and only one schema " errors
 			// I have never seen a useful one and they seem to always be
 			// accompanied by another more useful error that will be displayed
-			if err.ErrType == "number_one_of" {
+			if err.ErrType == errTypeNumberOneOf {
 				continue
 			}
 
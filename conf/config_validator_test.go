@@ -3,6 +3,7 @@
 package conf
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -204,3 +205,69 @@ func Test_jsonToConfigContext(t *testing.T) {
 		})
 	}
 }
+
+func Test_ValidationErrors_Reportable(t *testing.T) {
+	verrs := ValidationErrors{
+		Errors: VErrSlice{
+			{ValidationErr: asconfig.ValidationErr{Context: "b", Description: "d2", ErrType: "number_lte"}},
+			{ValidationErr: asconfig.ValidationErr{Context: "a", Description: "d1", ErrType: errTypeNumberOneOf}},
+			{ValidationErr: asconfig.ValidationErr{Context: "a", Description: "d0", ErrType: "required"}},
+		},
+	}
+
+	got := verrs.Reportable()
+	if len(got) != 2 {
+		t.Fatalf("Reportable() returned %d errors, want 2", len(got))
+	}
+
+	if got[0].Description != "d0" || got[1].Description != "d2" {
+		t.Errorf("Reportable() = %v, want errors sorted with number_one_of removed", got)
+	}
+
+	if verrs.Errors[0].Description != "d2" {
+		t.Errorf("Reportable() reordered the receiver's errors: %v", verrs.Errors)
+	}
+}
+
+func Test_ValidationError_MarshalJSON(t *testing.T) {
+	verr := ValidationError{
+		ValidationErr: asconfig.ValidationErr{
+			Context:     "namespaces.test",
+			Field:       "namespaces.test.replication-factor",
+			Description: "Must be less than or equal to 256",
+			ErrType:     "number_lte",
+			Value:       300,
+		},
+		Version: "7.0.0",
+	}
+
+	got, err := json.Marshal(verr)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+
+	want := `{"context":"namespaces.test","field":"namespaces.test.replication-factor",` +
+		`"description":"Must be less than or equal to 256","error-type":"number_lte","value":300,"version":"7.0.0"}`
+	if string(got) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", got, want)
+	}
+}
+
+func Test_ValidationErrors_Error(t *testing.T) {
+	verrs := ValidationErrors{
+		Errors: VErrSlice{
+			{
+				ValidationErr: asconfig.ValidationErr{Context: "service", Description: "d1", ErrType: "required"},
+				Line:          3,
+				Column:        1,
+			},
+			{ValidationErr: asconfig.ValidationErr{Context: "logging", Description: "d2", ErrType: "invalid_type"}},
+		},
+	}
+
+	want := "context: logging\n\t- description: d2, error-type: invalid_type\n" +
+		"context: service (line 3, column 1)\n\t- description: d1, error-type: required\n"
+	if got := verrs.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/go-logr/logr"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"gopkg.in/yaml.v3"
+)
+
+// HCL is the hcl config format. Each config section is a top level attribute
+// whose value has the structure of the yaml format, for example
+// namespaces = [{ name = "test", replication-factor = 2 }].
+const HCL asConf.Format = "hcl"
+
+var ErrInvalidHCL = errors.New("hcl config values must be literals")
+
+func init() {
+	RegisterFormat(HCL, FormatCodec{
+		Names:     []string{"hcl"},
+		Marshal:   marshalHCL,
+		Unmarshal: unmarshalHCL,
+	})
+}
+
+func marshalHCL(conf ConfHandler) ([]byte, error) {
+	// json is the bridge between go and cty values
+	src, err := json.Marshal(conf.ToMap())
+	if err != nil {
+		return nil, err
+	}
+
+	ty, err := ctyjson.ImpliedType(src)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := ctyjson.Unmarshal(src, ty)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := make([]string, 0, len(val.Type().AttributeTypes()))
+	for section := range val.Type().AttributeTypes() {
+		sections = append(sections, section)
+	}
+
+	sort.Strings(sections)
+
+	file := hclwrite.NewEmptyFile()
+	for _, section := range sections {
+		file.Body().SetAttributeValue(section, val.GetAttr(section))
+	}
+
+	return file.Bytes(), nil
+}
+
+func unmarshalHCL(log logr.Logger, src []byte) (*asConf.AsConfig, error) {
+	file, diags := hclsyntax.ParseConfig(src, "config.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	data := make(map[string]any, len(attrs))
+
+	for name, attr := range attrs {
+		// a nil context rejects variables and functions
+		val, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, errors.Join(ErrInvalidHCL, diags)
+		}
+
+		vsrc, err := ctyjson.Marshal(val, val.Type())
+		if err != nil {
+			return nil, err
+		}
+
+		// parsing json as yaml keeps integers integers
+		var section any
+		if err := yaml.Unmarshal(vsrc, &section); err != nil {
+			return nil, err
+		}
+
+		data[name] = section
+	}
+
+	return newASConfigFromMap(log, data)
+}
@@ -0,0 +1,32 @@
+package conf
+
+import (
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/go-logr/logr"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// TOML is the toml config format. It has the structure of the yaml format,
+// named sections such as namespaces are arrays of tables.
+const TOML asConf.Format = "toml"
+
+func init() {
+	RegisterFormat(TOML, FormatCodec{
+		Names:     []string{"toml"},
+		Marshal:   marshalTOML,
+		Unmarshal: unmarshalTOML,
+	})
+}
+
+func marshalTOML(conf ConfHandler) ([]byte, error) {
+	return toml.Marshal(conf.ToMap())
+}
+
+func unmarshalTOML(log logr.Logger, src []byte) (*asConf.AsConfig, error) {
+	var data map[string]any
+	if err := toml.Unmarshal(src, &data); err != nil {
+		return nil, err
+	}
+
+	return newASConfigFromMap(log, data)
+}
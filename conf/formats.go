@@ -0,0 +1,107 @@
+package conf
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v3"
+)
+
+// FormatCodec reads and writes configs in a config format.
+type FormatCodec struct {
+	// Names are the names of the format, which are also its file extensions.
+	// The first name is the extension of the files written in the format.
+	Names []string
+	// Marshal writes a config in the format.
+	Marshal func(conf ConfHandler) ([]byte, error)
+	// Unmarshal loads a config written in the format.
+	Unmarshal func(log logr.Logger, src []byte) (*asConf.AsConfig, error)
+}
+
+// formats is the registry of config formats. Formats are registered by the
+// init functions of the files that implement them.
+var formats = map[asConf.Format]FormatCodec{}
+
+// RegisterFormat adds a config format to the registry, replacing the codec of
+// a format that is already registered. It is not safe for concurrent use and
+// should be called from init functions.
+func RegisterFormat(format asConf.Format, codec FormatCodec) {
+	formats[format] = codec
+}
+
+// ParseFormat returns the registered format with the name or file extension.
+func ParseFormat(name string) (asConf.Format, error) {
+	name = strings.ToLower(name)
+
+	for format, codec := range formats {
+		if slices.Contains(codec.Names, name) {
+			return format, nil
+		}
+	}
+
+	return asConf.Invalid, fmt.Errorf("%w: %s", asConf.ErrInvalidFormat, name)
+}
+
+// FormatNames returns the names of the registered formats, sorted.
+func FormatNames() []string {
+	var res []string
+
+	for _, codec := range formats {
+		res = append(res, codec.Names...)
+	}
+
+	slices.Sort(res)
+
+	return res
+}
+
+// FormatExtension returns the extension of the files written in format.
+func FormatExtension(format asConf.Format) (string, error) {
+	codec, ok := formats[format]
+	if !ok || len(codec.Names) == 0 {
+		return "", fmt.Errorf("%w %s", asConf.ErrInvalidFormat, format)
+	}
+
+	return codec.Names[0], nil
+}
+
+func init() {
+	RegisterFormat(asConf.AeroConfig, FormatCodec{
+		Names: []string{"conf", "asconfig", "asconf"},
+		Marshal: func(conf ConfHandler) ([]byte, error) {
+			return []byte(conf.ToConfFile()), nil
+		},
+		Unmarshal: func(log logr.Logger, src []byte) (*asConf.AsConfig, error) {
+			return asConf.NewASConfigFromBytes(log, src, asConf.AeroConfig)
+		},
+	})
+
+	RegisterFormat(asConf.YAML, FormatCodec{
+		Names: []string{"yaml", "yml"},
+		Marshal: func(conf ConfHandler) ([]byte, error) {
+			return yaml.Marshal(conf.ToMap())
+		},
+		Unmarshal: func(log logr.Logger, src []byte) (*asConf.AsConfig, error) {
+			return asConf.NewASConfigFromBytes(log, src, asConf.YAML)
+		},
+	})
+
+	RegisterFormat(JSON, FormatCodec{
+		Names:     []string{"json"},
+		Marshal:   marshalJSON,
+		Unmarshal: unmarshalJSON,
+	})
+}
+
+func marshalJSON(conf ConfHandler) ([]byte, error) {
+	text, err := json.MarshalIndent(conf.ToMap(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(text, '\n'), nil
+}
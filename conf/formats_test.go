@@ -0,0 +1,121 @@
+//go:build unit
+
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	asConf "github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/go-logr/logr"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    asConf.Format
+		wantErr error
+	}{
+		{name: "yml", want: asConf.YAML},
+		{name: "CONF", want: asConf.AeroConfig},
+		{name: "asconfig", want: asConf.AeroConfig},
+		{name: "json", want: JSON},
+		{name: "toml", want: TOML},
+		{name: "hcl", want: HCL},
+		{name: "ini", want: asConf.Invalid, wantErr: asConf.ErrInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormat(tt.name)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseFormat() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if names := strings.Join(FormatNames(), ", "); names != "asconf, asconfig, conf, hcl, json, toml, yaml, yml" {
+		t.Errorf("FormatNames() = %s", names)
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	const upper asConf.Format = "upper"
+
+	RegisterFormat(upper, FormatCodec{
+		Names: []string{"upper"},
+		Marshal: func(conf ConfHandler) ([]byte, error) {
+			return []byte(strings.ToUpper(conf.ToConfFile())), nil
+		},
+	})
+	defer delete(formats, upper)
+
+	cfg := &mockCFG{confText: "namespace ns1 {}"}
+
+	got, err := NewConfigMarshaller(cfg, upper).MarshalText()
+	if err != nil || string(got) != "NAMESPACE NS1 {}" {
+		t.Errorf("MarshalText() = %q, %v, want the registered format", got, err)
+	}
+
+	if ext, err := FormatExtension(upper); err != nil || ext != "upper" {
+		t.Errorf("FormatExtension() = %q, %v, want upper", ext, err)
+	}
+}
+
+func TestFormatsRoundTrip(t *testing.T) {
+	src, err := asConf.NewASConfigFromBytes(logr.Discard(), []byte(`
+service:
+  proto-fd-max: 15000
+  cluster-name: cl1
+logging:
+  - name: console
+    any: info
+namespaces:
+  - name: test
+    replication-factor: 2
+    storage-engine:
+      type: device
+      files:
+        - /opt/test.dat
+      filesize: 4294967296
+  - name: bar
+    replication-factor: 1
+    storage-engine:
+      type: memory
+`), asConf.YAML)
+	if err != nil {
+		t.Fatalf("NewASConfigFromBytes() error = %v", err)
+	}
+
+	for _, format := range []asConf.Format{asConf.YAML, asConf.AeroConfig, JSON, TOML, HCL} {
+		t.Run(string(format), func(t *testing.T) {
+			text, err := NewConfigMarshaller(src, format).MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() error = %v", err)
+			}
+
+			got, err := NewASConfigFromBytes(logr.Discard(), text, format)
+			if err != nil {
+				t.Fatalf("NewASConfigFromBytes() error = %v\n%s", err, text)
+			}
+
+			// the Aerospike config format loads numbers with other integer types
+			if fmt.Sprint(*got.GetFlatMap()) != fmt.Sprint(*src.GetFlatMap()) {
+				t.Errorf("round trip = %v, want %v", *got.GetFlatMap(), *src.GetFlatMap())
+			}
+		})
+	}
+}
+
+func TestUnmarshalHCLExpressions(t *testing.T) {
+	_, err := NewASConfigFromBytes(logr.Discard(), []byte("service = { proto-fd-max = var.fds }\n"), HCL)
+	if !errors.Is(err, ErrInvalidHCL) {
+		t.Errorf("NewASConfigFromBytes() error = %v, want %v", err, ErrInvalidHCL)
+	}
+}
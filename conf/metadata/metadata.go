@@ -1,6 +1,8 @@
 package metadata
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"sort"
@@ -8,6 +10,16 @@ import (
 
 const commentChar = "#"
 
+// Header and Footer are the comment lines around the metadata written by asconfig.
+const (
+	Header = commentChar + " *** Aerospike Metadata Generated by Asconfig ***"
+	Footer = commentChar + " *** End Aerospike Metadata ***"
+)
+
+// JSONField is the reserved field of json configs that holds their metadata,
+// json has no comments to carry it.
+const JSONField = "_asconfig"
+
 // findComments matches text of the form `<commentChar> <key>: <val>`.
 // for example, parsing...
 // # comment about metadata
@@ -18,6 +30,8 @@ const commentChar = "#"
 var findComments = regexp.MustCompile(commentChar + `(?m)\s*(.+):\s*(.+)\s*$`)
 
 func Unmarshal(src []byte, dst map[string]string) error {
+	unmarshalJSON(src, dst)
+
 	matches := findComments.FindAllSubmatch(src, -1)
 
 	for _, match := range matches {
@@ -33,6 +47,25 @@ func Unmarshal(src []byte, dst map[string]string) error {
 	return nil
 }
 
+// UnmarshalBlock is like Unmarshal but only reads the metadata between the Header
+// and Footer lines, or in the JSONField of json configs. Comments elsewhere in src,
+// such as "# owner: ops", are left to the config.
+func UnmarshalBlock(src []byte, dst map[string]string) error {
+	unmarshalJSON(src, dst)
+
+	start := bytes.Index(src, []byte(Header))
+	if start < 0 {
+		return nil
+	}
+
+	block := src[start+len(Header):]
+	if end := bytes.Index(block, []byte(Footer)); end >= 0 {
+		block = block[:end]
+	}
+
+	return Unmarshal(block, dst)
+}
+
 func formatLine(k string, v any) string {
 	fmtStr := "%s %s: %v"
 	return fmt.Sprintf(fmtStr, commentChar, k, v)
@@ -54,3 +87,47 @@ func Marshal(src map[string]string) ([]byte, error) {
 
 	return res, nil
 }
+
+// unmarshalJSON saves the metadata in the JSONField of src if src is a json object.
+func unmarshalJSON(src []byte, dst map[string]string) {
+	if !bytes.HasPrefix(bytes.TrimSpace(src), []byte("{")) {
+		return
+	}
+
+	var doc struct {
+		Metadata map[string]any `json:"_asconfig"`
+	}
+
+	if err := json.Unmarshal(src, &doc); err != nil {
+		return
+	}
+
+	for k, v := range doc.Metadata {
+		if _, ok := dst[k]; !ok {
+			dst[k] = fmt.Sprint(v)
+		}
+	}
+}
+
+// MarshalJSON adds src to the JSONField of the json object doc.
+func MarshalJSON(doc []byte, src map[string]string) ([]byte, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &obj); err != nil {
+		return nil, err
+	}
+
+	mdata, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+
+	obj[JSONField] = mdata
+
+	// JSONField sorts before config sections so the metadata leads the document
+	res, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(res, '\n'), nil
+}
@@ -56,6 +56,17 @@ namespace ns1 {
 	# other-item: a long value
 `
 
+var testJSON = `{
+  "_asconfig": {
+    "aerospike-server-version": "7.0.0",
+    "asconfig-version": "0.12.0"
+  },
+  "service": {
+    "proto-fd-max": 15000
+  }
+}
+`
+
 func TestUnmarshal(t *testing.T) {
 	type args struct {
 		src []byte
@@ -102,6 +113,18 @@ func TestUnmarshal(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "json",
+			args: args{
+				src: []byte(testJSON),
+				dst: map[string]string{},
+			},
+			want: map[string]string{
+				"aerospike-server-version": "7.0.0",
+				"asconfig-version":         "0.12.0",
+			},
+			wantErr: false,
+		},
 		{
 			name: "t4",
 			args: args{
@@ -127,6 +150,52 @@ func TestUnmarshal(t *testing.T) {
 	}
 }
 
+var testConfBlock = `# owner: ops
+# *** Aerospike Metadata Generated by Asconfig ***
+# aerospike-server-version: 7.0.0
+# *** End Aerospike Metadata ***
+
+service {
+	proto-fd-max 15000 # TODO: raise later
+}
+`
+
+func TestUnmarshalBlock(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want map[string]string
+	}{
+		{
+			name: "block",
+			src:  testConfBlock,
+			want: map[string]string{"aerospike-server-version": "7.0.0"},
+		},
+		{
+			name: "no block",
+			src:  testConfPartialMeta,
+			want: map[string]string{},
+		},
+		{
+			name: "json",
+			src:  testJSON,
+			want: map[string]string{"aerospike-server-version": "7.0.0", "asconfig-version": "0.12.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			if err := metadata.UnmarshalBlock([]byte(tt.src), got); err != nil {
+				t.Fatalf("UnmarshalBlock() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmarshalBlock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 var testMarshalMetaComplete = `# aerospike-server-version: 7.0.0.0
 # asadm-version: 2.20.0
 # asconfig-version: 0.12.0
@@ -192,3 +261,24 @@ func TestMarshalText(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalJSON(t *testing.T) {
+	doc := []byte("{\n  \"service\": {\n    \"proto-fd-max\": 15000\n  }\n}\n")
+	src := map[string]string{
+		"aerospike-server-version": "7.0.0",
+		"asconfig-version":         "0.12.0",
+	}
+
+	got, err := metadata.MarshalJSON(doc, src)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+
+	if string(got) != testJSON {
+		t.Errorf("MarshalJSON() = %s, want %s", got, testJSON)
+	}
+
+	if _, err := metadata.MarshalJSON([]byte("service {}"), src); err == nil {
+		t.Errorf("MarshalJSON() of a conf file error = nil, want an error")
+	}
+}
@@ -0,0 +1,267 @@
+package conf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidPolicy         = errors.New("invalid policy")
+	ErrPolicyMissingID       = errors.New("policy rule is missing an id")
+	ErrPolicyMissingPath     = errors.New("policy rule is missing a path")
+	ErrPolicyUnknownSeverity = errors.New("policy rule has an unknown severity")
+	ErrPolicyNoChecks        = errors.New("policy rule has no checks")
+)
+
+const (
+	// policyWildcard matches every item of a list, or every key of a map, in a policy path.
+	policyWildcard = "*"
+	// policyListWildcard is the list form of policyWildcard. Ex: namespaces[*].name
+	policyListWildcard = "[*]"
+)
+
+// Policy is a set of house rules that configurations must follow in
+// addition to the schema. Policies are written in yaml.
+//
+//	rules:
+//	  - id: heartbeat-tls
+//	    description: heartbeat must use TLS
+//	    path: network.heartbeat.tls-name
+//	    required: true
+//	  - id: proto-fd-max
+//	    path: service.proto-fd-max
+//	    min: 100000
+//	  - id: namespace-names
+//	    severity: warning
+//	    path: namespaces[*].name
+//	    pattern: ^[a-z][a-z0-9-]*$
+type Policy struct {
+	Rules []PolicyRule `yaml:"rules"`
+}
+
+// PolicyRule checks the values found at Path. Path is a dotted configuration
+// path in the format used by validation error contexts, where "*" or "[*]"
+// matches all list items or map keys. List items are matched by name.
+// Each of the checks that is set must pass for every value.
+type PolicyRule struct {
+	ID          string   `yaml:"id"`
+	Description string   `yaml:"description"`
+	Severity    Severity `yaml:"severity"`
+	Path        string   `yaml:"path"`
+
+	// Required fails if the path is missing.
+	Required bool `yaml:"required"`
+	// Min and Max bound numeric values.
+	Min *float64 `yaml:"min"`
+	Max *float64 `yaml:"max"`
+	// Pattern is a regular expression that values must match.
+	Pattern string `yaml:"pattern"`
+	// Enum lists the allowed values.
+	Enum []any `yaml:"enum"`
+}
+
+// LoadPolicy parses a yaml policy and returns its rules.
+func LoadPolicy(data []byte) ([]Rule, error) {
+	var policy Policy
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	// an empty policy has no rules
+	if err := dec.Decode(&policy); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+	}
+
+	res := make([]Rule, 0, len(policy.Rules))
+
+	for i, pr := range policy.Rules {
+		rule, err := pr.toRule()
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %w", ErrInvalidPolicy, i, err)
+		}
+
+		res = append(res, rule)
+	}
+
+	return res, nil
+}
+
+// toRule validates pr and converts it to a Rule.
+func (pr PolicyRule) toRule() (Rule, error) {
+	if pr.ID == "" {
+		return Rule{}, ErrPolicyMissingID
+	}
+
+	if pr.Path == "" {
+		return Rule{}, fmt.Errorf("%w: %s", ErrPolicyMissingPath, pr.ID)
+	}
+
+	switch pr.Severity {
+	case "":
+		pr.Severity = SeverityError
+	case SeverityError, SeverityWarning, SeverityInfo:
+	default:
+		return Rule{}, fmt.Errorf("%w: %s: %q", ErrPolicyUnknownSeverity, pr.ID, pr.Severity)
+	}
+
+	if !pr.Required && pr.Min == nil && pr.Max == nil && pr.Pattern == "" && len(pr.Enum) == 0 {
+		return Rule{}, fmt.Errorf("%w: %s", ErrPolicyNoChecks, pr.ID)
+	}
+
+	var pattern *regexp.Regexp
+
+	if pr.Pattern != "" {
+		var err error
+
+		pattern, err = regexp.Compile(pr.Pattern)
+		if err != nil {
+			return Rule{}, fmt.Errorf("%s: %w", pr.ID, err)
+		}
+	}
+
+	path := strings.Split(strings.ReplaceAll(pr.Path, policyListWildcard, "."+policyWildcard), ".")
+
+	description := pr.Description
+	if description == "" {
+		description = "violates policy " + pr.ID
+	}
+
+	return Rule{
+		ID:          pr.ID,
+		Severity:    pr.Severity,
+		Description: description,
+		Check: func(config map[string]any, _ string, _ RuleOptions) []RuleViolation {
+			var res []RuleViolation
+
+			for _, m := range matchPolicyPath(config, path, "") {
+				if problem := pr.check(m, pattern); problem != "" {
+					res = append(res, RuleViolation{
+						Context:     m.context,
+						Description: description + ": " + problem,
+					})
+				}
+			}
+
+			return res
+		},
+	}, nil
+}
+
+// check returns a description of the problem with m, or an empty string if m passes.
+func (pr PolicyRule) check(m policyMatch, pattern *regexp.Regexp) string {
+	if !m.found {
+		if pr.Required {
+			return "missing"
+		}
+
+		return ""
+	}
+
+	if pr.Min != nil || pr.Max != nil {
+		num, ok := numberValue(m.value)
+		if !ok {
+			return fmt.Sprintf("%v is not a number", m.value)
+		}
+
+		if pr.Min != nil && num < *pr.Min {
+			return fmt.Sprintf("%v is less than %v", m.value, *pr.Min)
+		}
+
+		if pr.Max != nil && num > *pr.Max {
+			return fmt.Sprintf("%v is greater than %v", m.value, *pr.Max)
+		}
+	}
+
+	if pattern != nil && !pattern.MatchString(policyValueString(m.value)) {
+		return fmt.Sprintf("%v does not match %s", m.value, pattern)
+	}
+
+	if len(pr.Enum) > 0 {
+		allowed := make([]string, len(pr.Enum))
+		for i, v := range pr.Enum {
+			allowed[i] = policyValueString(v)
+		}
+
+		if !slices.Contains(allowed, policyValueString(m.value)) {
+			return fmt.Sprintf("%v is not one of %s", m.value, strings.Join(allowed, ", "))
+		}
+	}
+
+	return ""
+}
+
+// policyMatch is a value found at a policy path.
+type policyMatch struct {
+	context string
+	value   any
+	found   bool
+}
+
+// matchPolicyPath returns the values at path below node. Paths that don't
+// exist are returned as a single match that is not found.
+func matchPolicyPath(node any, path []string, context string) []policyMatch {
+	if len(path) == 0 {
+		return []policyMatch{{context: context, value: node, found: true}}
+	}
+
+	key, rest := path[0], path[1:]
+
+	switch val := node.(type) {
+	case map[string]any:
+		if key == policyWildcard {
+			var res []policyMatch
+
+			for k, child := range val {
+				res = append(res, matchPolicyPath(child, rest, JoinContext(context, k))...)
+			}
+
+			slices.SortFunc(res, func(a, b policyMatch) int { return strings.Compare(a.context, b.context) })
+
+			return res
+		}
+
+		if child, ok := val[key]; ok {
+			return matchPolicyPath(child, rest, JoinContext(context, key))
+		}
+	case []any:
+		var res []policyMatch
+
+		for i, item := range val {
+			name := strconv.Itoa(i)
+
+			if itemMap, ok := item.(map[string]any); ok {
+				if n, ok := itemMap[keyName].(string); ok {
+					name = n
+				}
+			}
+
+			if key == policyWildcard || key == name {
+				res = append(res, matchPolicyPath(item, rest, JoinContext(context, name))...)
+			}
+		}
+
+		if len(res) > 0 {
+			return res
+		}
+	}
+
+	return []policyMatch{{context: JoinContext(context, strings.Join(path, "."))}}
+}
+
+// policyValueString formats a policy or configuration value for comparison.
+// Whole json numbers are formatted without a fraction.
+func policyValueString(v any) string {
+	if num, ok := v.(float64); ok {
+		return strconv.FormatFloat(num, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,106 @@
+//go:build unit
+
+package conf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr error
+	}{
+		{name: "empty", policy: ""},
+		{
+			name: "valid",
+			policy: `
+rules:
+  - id: proto-fd-max
+    path: service.proto-fd-max
+    min: 100000
+`,
+		},
+		{name: "unknown field", policy: "rules:\n  - id: a\n    path: b\n    minimum: 1\n", wantErr: ErrInvalidPolicy},
+		{name: "missing id", policy: "rules:\n  - path: a\n    required: true\n", wantErr: ErrPolicyMissingID},
+		{name: "missing path", policy: "rules:\n  - id: a\n    required: true\n", wantErr: ErrPolicyMissingPath},
+		{name: "no checks", policy: "rules:\n  - id: a\n    path: b\n", wantErr: ErrPolicyNoChecks},
+		{
+			name:    "unknown severity",
+			policy:  "rules:\n  - id: a\n    path: b\n    required: true\n    severity: fatal\n",
+			wantErr: ErrPolicyUnknownSeverity,
+		},
+		{name: "bad pattern", policy: "rules:\n  - id: a\n    path: b\n    pattern: '['\n", wantErr: ErrInvalidPolicy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadPolicy([]byte(tt.policy))
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("LoadPolicy() error = %v", err)
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadPolicy() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicyRules(t *testing.T) {
+	policy := `
+rules:
+  - id: heartbeat-tls
+    path: network.heartbeat.tls-name
+    required: true
+  - id: proto-fd-max
+    severity: warning
+    path: service.proto-fd-max
+    min: 100000
+  - id: namespace-names
+    path: namespaces[*].name
+    pattern: ^[a-z]+$
+  - id: replication-factor
+    path: namespaces.*.replication-factor
+    enum: [2, 3]
+`
+	config := `{
+		"service": {"proto-fd-max": 15000},
+		"network": {"heartbeat": {"mode": "mesh"}},
+		"namespaces": [
+			{"name": "test", "replication-factor": 2},
+			{"name": "Bar1", "replication-factor": 1}
+		]
+	}`
+
+	rules, err := LoadPolicy([]byte(policy))
+	if err != nil {
+		t.Fatalf("LoadPolicy() error = %v", err)
+	}
+
+	got := checkRules(rules, testRuleConfig(t, config), "7.0.0", RuleOptions{})
+
+	want := []struct {
+		ruleID   string
+		context  string
+		severity Severity
+	}{
+		{"heartbeat-tls", "network.heartbeat.tls-name", SeverityError},
+		{"proto-fd-max", "service.proto-fd-max", SeverityWarning},
+		{"namespace-names", "namespaces.Bar1.name", SeverityError},
+		{"replication-factor", "namespaces.Bar1.replication-factor", SeverityError},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("checkRules() = %+v, want %d violations", got, len(want))
+	}
+
+	for i, w := range want {
+		if got[i].RuleID != w.ruleID || got[i].Context != w.context || got[i].Severity != w.severity {
+			t.Errorf("checkRules()[%d] = %s %s %s, want %s %s %s", i,
+				got[i].RuleID, got[i].Context, got[i].Severity, w.ruleID, w.context, w.severity)
+		}
+	}
+}
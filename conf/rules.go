@@ -0,0 +1,270 @@
+package conf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aerospike/aerospike-management-lib/asconfig"
+)
+
+// Severity is how serious a validation error is. Only SeverityError
+// makes a configuration invalid.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// errTypeRuleViolation is the error type of semantic rule violations.
+const errTypeRuleViolation = "rule_violation"
+
+// RuleOptions are facts about the deployment that a configuration
+// file does not contain. Rules that need an unset option are skipped.
+type RuleOptions struct {
+	// RackCount is the number of racks in the cluster.
+	RackCount int
+	// HostMemory is the memory available on the host in bytes.
+	HostMemory uint64
+}
+
+// RuleViolation is a single problem found by a Rule.
+type RuleViolation struct {
+	// Context is the dotted, name resolved path of the offending
+	// key or section. Ex: namespaces.test.replication-factor
+	Context     string
+	Description string
+}
+
+// RuleCheck inspects config, the json representation of a configuration
+// as produced by ToMap, for the given server version.
+type RuleCheck func(config map[string]any, version string, opts RuleOptions) []RuleViolation
+
+// Rule is a semantic, often cross field, check that can't be expressed by the
+// json schema. Rules run after schema validation and must not assume the
+// configuration matched the schema.
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+	Check       RuleCheck
+}
+
+// DefaultRules returns the semantic rules that asconfig checks by default.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			ID:          "replication-factor-exceeds-racks",
+			Severity:    SeverityWarning,
+			Description: "replication-factor is larger than the number of racks",
+			Check:       checkReplicationFactorRacks,
+		},
+		{
+			ID:          "strong-consistency-default-ttl",
+			Severity:    SeverityError,
+			Description: "strong-consistency namespaces must use a default-ttl of 0",
+			Check:       checkStrongConsistencyTTL,
+		},
+		{
+			ID:          "overlapping-storage-paths",
+			Severity:    SeverityError,
+			Description: "storage devices and files must not be shared by namespaces",
+			Check:       checkOverlappingStoragePaths,
+		},
+		{
+			ID:          "memory-exceeds-host",
+			Severity:    SeverityError,
+			Description: "namespace memory sizes sum to more than the host memory",
+			Check:       checkMemoryExceedsHost,
+		},
+	}
+}
+
+// checkRules runs rules against config and converts their violations to validation errors.
+func checkRules(rules []Rule, config map[string]any, version string, opts RuleOptions) VErrSlice {
+	res := VErrSlice{}
+
+	for _, rule := range rules {
+		for _, violation := range rule.Check(config, version, opts) {
+			res = append(res, ValidationError{
+				ValidationErr: asconfig.ValidationErr{
+					Context:     violation.Context,
+					Field:       violation.Context,
+					Description: violation.Description,
+					ErrType:     errTypeRuleViolation,
+				},
+				Version:  version,
+				RuleID:   rule.ID,
+				Severity: rule.Severity,
+			})
+		}
+	}
+
+	return res
+}
+
+// namespaceConfigs returns the named namespaces of config in a stable order.
+func namespaceConfigs(config map[string]any) []map[string]any {
+	list, _ := config["namespaces"].([]any)
+	res := make([]map[string]any, 0, len(list))
+
+	for _, item := range list {
+		ns, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if _, ok := ns[keyName].(string); !ok {
+			continue
+		}
+
+		res = append(res, ns)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i][keyName].(string) < res[j][keyName].(string) //nolint:errcheck // checked above
+	})
+
+	return res
+}
+
+// namespaceContext returns the context of key in the namespace ns.
+func namespaceContext(ns map[string]any, key ...string) string {
+	return strings.Join(append([]string{"namespaces", ns[keyName].(string)}, key...), ".") //nolint:errcheck // checked by namespaceConfigs
+}
+
+// numberValue returns v as a float64 if it is a json number.
+func numberValue(v any) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func checkReplicationFactorRacks(config map[string]any, _ string, opts RuleOptions) []RuleViolation {
+	if opts.RackCount < 1 {
+		return nil
+	}
+
+	var res []RuleViolation
+
+	for _, ns := range namespaceConfigs(config) {
+		rf, ok := numberValue(ns["replication-factor"])
+		if !ok || int(rf) <= opts.RackCount {
+			continue
+		}
+
+		res = append(res, RuleViolation{
+			Context: namespaceContext(ns, "replication-factor"),
+			Description: fmt.Sprintf(
+				"replication-factor %d is larger than the rack count %d, some racks will hold more than one replica",
+				int(rf), opts.RackCount,
+			),
+		})
+	}
+
+	return res
+}
+
+func checkStrongConsistencyTTL(config map[string]any, _ string, _ RuleOptions) []RuleViolation {
+	var res []RuleViolation
+
+	for _, ns := range namespaceConfigs(config) {
+		if sc, _ := ns["strong-consistency"].(bool); !sc {
+			continue
+		}
+
+		// records may expire in strong-consistency mode when expunges are allowed
+		if expunge, _ := ns["strong-consistency-allow-expunge"].(bool); expunge {
+			continue
+		}
+
+		ttl, ok := numberValue(ns["default-ttl"])
+		if !ok || ttl == 0 {
+			continue
+		}
+
+		res = append(res, RuleViolation{
+			Context: namespaceContext(ns, "default-ttl"),
+			Description: fmt.Sprintf(
+				"default-ttl is %d but strong-consistency namespaces require a default-ttl of 0",
+				int64(ttl),
+			),
+		})
+	}
+
+	return res
+}
+
+func checkOverlappingStoragePaths(config map[string]any, _ string, _ RuleOptions) []RuleViolation {
+	var res []RuleViolation
+
+	owners := map[string]string{}
+
+	for _, ns := range namespaceConfigs(config) {
+		engine, _ := ns["storage-engine"].(map[string]any)
+
+		for _, key := range []string{"devices", "files"} {
+			paths, _ := engine[key].([]any)
+
+			for _, entry := range paths {
+				str, _ := entry.(string)
+
+				// each entry may also name a shadow device, Ex: "/dev/sda /dev/sdb"
+				for _, path := range strings.Fields(str) {
+					name := ns[keyName].(string) //nolint:errcheck // checked by namespaceConfigs
+
+					owner, ok := owners[path]
+					if !ok {
+						owners[path] = name
+						continue
+					}
+
+					if owner == name {
+						continue
+					}
+
+					res = append(res, RuleViolation{
+						Context:     namespaceContext(ns, "storage-engine", key),
+						Description: fmt.Sprintf("%s is also used by namespace %s", path, owner),
+					})
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+func checkMemoryExceedsHost(config map[string]any, _ string, opts RuleOptions) []RuleViolation {
+	if opts.HostMemory == 0 {
+		return nil
+	}
+
+	var total float64
+
+	for _, ns := range namespaceConfigs(config) {
+		// memory-size was replaced by the storage-engine memory data-size in server 7.0
+		if size, ok := numberValue(ns["memory-size"]); ok {
+			total += size
+		}
+
+		if engine, ok := ns["storage-engine"].(map[string]any); ok {
+			if size, ok := numberValue(engine["data-size"]); ok {
+				total += size
+			}
+		}
+	}
+
+	if total <= float64(opts.HostMemory) {
+		return nil
+	}
+
+	return []RuleViolation{{
+		Context: "namespaces",
+		Description: fmt.Sprintf(
+			"namespace memory sizes sum to %d bytes which is more than the host memory of %d bytes",
+			uint64(total), opts.HostMemory,
+		),
+	}}
+}
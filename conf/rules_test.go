@@ -0,0 +1,170 @@
+//go:build unit
+
+package conf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/go-logr/logr"
+)
+
+// testRuleConfig returns a config in the generic json form that rules receive.
+func testRuleConfig(t *testing.T, src string) map[string]any {
+	t.Helper()
+
+	res := map[string]any{}
+	if err := json.Unmarshal([]byte(src), &res); err != nil {
+		t.Fatalf("invalid test config: %v", err)
+	}
+
+	return res
+}
+
+func TestDefaultRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		opts     RuleOptions
+		want     []string
+		contexts []string
+	}{
+		{
+			name:   "no namespaces",
+			config: `{"service": {"proto-fd-max": 15000}}`,
+			opts:   RuleOptions{RackCount: 2, HostMemory: 1024},
+		},
+		{
+			name: "replication-factor larger than racks",
+			config: `{"namespaces": [
+				{"name": "test", "replication-factor": 3},
+				{"name": "bar", "replication-factor": 2}
+			]}`,
+			opts:     RuleOptions{RackCount: 2},
+			want:     []string{"replication-factor-exceeds-racks"},
+			contexts: []string{"namespaces.test.replication-factor"},
+		},
+		{
+			name:   "replication-factor skipped without rack count",
+			config: `{"namespaces": [{"name": "test", "replication-factor": 3}]}`,
+		},
+		{
+			name: "strong-consistency with default-ttl",
+			config: `{"namespaces": [
+				{"name": "test", "strong-consistency": true, "default-ttl": 3600},
+				{"name": "bar", "strong-consistency": true, "default-ttl": 0},
+				{"name": "baz", "strong-consistency": true, "strong-consistency-allow-expunge": true, "default-ttl": 60},
+				{"name": "ap", "default-ttl": 60}
+			]}`,
+			want:     []string{"strong-consistency-default-ttl"},
+			contexts: []string{"namespaces.test.default-ttl"},
+		},
+		{
+			name: "overlapping storage devices",
+			config: `{"namespaces": [
+				{"name": "test", "storage-engine": {"type": "device", "devices": ["/dev/sda /dev/sdc", "/dev/sdb"]}},
+				{"name": "bar", "storage-engine": {"type": "device", "devices": ["/dev/sdd /dev/sdc"]}},
+				{"name": "baz", "storage-engine": {"type": "device", "files": ["/opt/baz.dat"]}}
+			]}`,
+			want:     []string{"overlapping-storage-paths"},
+			contexts: []string{"namespaces.test.storage-engine.devices"},
+		},
+		{
+			name: "memory exceeds host",
+			config: `{"namespaces": [
+				{"name": "test", "memory-size": 1024},
+				{"name": "bar", "storage-engine": {"type": "memory", "data-size": 1024}}
+			]}`,
+			opts:     RuleOptions{HostMemory: 2000},
+			want:     []string{"memory-exceeds-host"},
+			contexts: []string{"namespaces"},
+		},
+		{
+			name:   "memory fits host",
+			config: `{"namespaces": [{"name": "test", "memory-size": 1024}]}`,
+			opts:   RuleOptions{HostMemory: 1024},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkRules(DefaultRules(), testRuleConfig(t, tt.config), "7.0.0", tt.opts)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("checkRules() = %v, want rules %v", got, tt.want)
+			}
+
+			for i, verr := range got {
+				if verr.RuleID != tt.want[i] {
+					t.Errorf("checkRules()[%d].RuleID = %s, want %s", i, verr.RuleID, tt.want[i])
+				}
+
+				if verr.Context != tt.contexts[i] {
+					t.Errorf("checkRules()[%d].Context = %s, want %s", i, verr.Context, tt.contexts[i])
+				}
+
+				if verr.Severity == "" || verr.Version != "7.0.0" {
+					t.Errorf("checkRules()[%d] = %+v, want severity and version set", i, verr)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigValidator_WithRules(t *testing.T) {
+	cfg := &mockCFG{
+		valid: true,
+		confMap: &asconfig.Conf{
+			"namespaces": []asconfig.Conf{
+				{"name": "test", "replication-factor": 3},
+			},
+		},
+	}
+
+	warn := Rule{
+		ID:       "test-warning",
+		Severity: SeverityWarning,
+		Check: func(map[string]any, string, RuleOptions) []RuleViolation {
+			return []RuleViolation{{Context: "namespaces.test", Description: "warning"}}
+		},
+	}
+
+	fail := Rule{
+		ID:       "test-error",
+		Severity: SeverityError,
+		Check: func(map[string]any, string, RuleOptions) []RuleViolation {
+			return []RuleViolation{{Context: "namespaces.test.replication-factor", Description: "error"}}
+		},
+	}
+
+	positions := SourcePositions{"namespaces.test": {Line: 3, Column: 1}}
+
+	verrs, err := NewConfigValidator(cfg, logr.Discard(), "7.0.0").
+		WithSourcePositions(positions).
+		WithRules(RuleOptions{}, warn).
+		Validate()
+	if err != nil {
+		t.Fatalf("Validate() with warnings returned error %v", err)
+	}
+
+	if len(verrs.Errors) != 1 || verrs.HasErrors() {
+		t.Fatalf("Validate() = %+v, want a single warning", verrs.Errors)
+	}
+
+	if verrs.Errors[0].Line != 3 {
+		t.Errorf("Validate() warning line = %d, want 3", verrs.Errors[0].Line)
+	}
+
+	verrs, err = NewConfigValidator(cfg, logr.Discard(), "7.0.0").
+		WithSourcePositions(positions).
+		WithRules(RuleOptions{}, warn, fail).
+		Validate()
+	if err == nil {
+		t.Fatalf("Validate() with rule errors returned nil error")
+	}
+
+	if len(verrs.Errors) != 2 || !verrs.HasErrors() {
+		t.Fatalf("Validate() = %+v, want a warning and an error", verrs.Errors)
+	}
+}
@@ -0,0 +1,218 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/aerospike/aerospike-management-lib/asconfig"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aerospike/asconfig/conf/metadata"
+)
+
+var ErrLayoutFormat = errors.New("the source layout can only be kept in the yaml and conf formats")
+
+// SourceComment is the comments attached to a key or section of a config source.
+// Head comments precede it, the line comment follows it on the same line and foot
+// comments follow it, such as the comments at the end of the enclosing section.
+// Comments keep their comment chars, comment lines are separated by newlines.
+type SourceComment struct {
+	Head string
+	Line string
+	Foot string
+}
+
+// SourceLayout is the order and the comments of the keys and sections of a
+// config source. The context "" holds the comments of the whole source.
+// Keys in the Aerospike config format are recorded with their yaml names.
+type SourceLayout struct {
+	Positions SourcePositions
+	Comments  map[string]SourceComment
+}
+
+// NewSourceLayout records the layout of src. Comments are recorded for the yaml
+// and Aerospike config formats, and the order of keys for json as well.
+// Metadata comments are not recorded since asconfig writes them again.
+func NewSourceLayout(src []byte, format asconfig.Format) (*SourceLayout, error) {
+	var (
+		comments map[string]SourceComment
+		err      error
+	)
+
+	switch format {
+	case asconfig.YAML:
+		comments, err = yamlSourceComments(src)
+	case asconfig.AeroConfig:
+		comments, err = confSourceComments(src)
+	case JSON:
+		comments = map[string]SourceComment{}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrLayoutFormat, format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := NewSourcePositions(src, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SourceLayout{Positions: positions, Comments: comments}, nil
+}
+
+// order returns the sort key of the first of contexts found in the source.
+// Contexts that are not in the source sort last.
+func (sl *SourceLayout) order(contexts ...string) int {
+	for _, context := range contexts {
+		if pos, ok := sl.Positions[context]; ok {
+			return pos.Line
+		}
+	}
+
+	return math.MaxInt
+}
+
+// addComment appends comment to the comments of context.
+func addComment(comments map[string]SourceComment, context string, head, line, foot string) {
+	c := comments[context]
+	c.Head = joinComments(c.Head, stripMetadata(head))
+	c.Line = strings.TrimSpace(c.Line + " " + line)
+	c.Foot = joinComments(c.Foot, stripMetadata(foot))
+
+	if c != (SourceComment{}) {
+		comments[context] = c
+	}
+}
+
+// joinComments joins comment lines, ignoring empty comments.
+func joinComments(comments ...string) string {
+	return strings.Join(slices.DeleteFunc(comments, func(c string) bool { return c == "" }), "\n")
+}
+
+// stripMetadata removes the metadata block written by asconfig from comment.
+func stripMetadata(comment string) string {
+	var (
+		res    []string
+		inMeta bool
+	)
+
+	for _, line := range strings.Split(comment, "\n") {
+		switch {
+		case line == metadata.Header:
+			inMeta = true
+		case line == metadata.Footer:
+			inMeta = false
+		case !inMeta:
+			res = append(res, line)
+		}
+	}
+
+	return strings.Trim(strings.Join(res, "\n"), "\n")
+}
+
+// confSourceComments records the comments of an Aerospike .conf file. Comment
+// lines are attached to the next key or section, or to the last key of the
+// section they end. Leading comments followed by a blank line are attached to
+// the whole source.
+func confSourceComments(src []byte) (map[string]SourceComment, error) {
+	res := map[string]SourceComment{}
+
+	var pending []string
+
+	// last holds the context of the last key or section at each depth
+	last := []string{""}
+
+	err := scanConf(src, func(line confLine) {
+		switch line.kind {
+		case confComment:
+			pending = append(pending, line.comment)
+		case confKey, confOpen:
+			context := line.context
+			if line.plural != "" {
+				context = line.plural
+			}
+
+			addComment(res, context, strings.Join(pending, "\n"), line.comment, "")
+
+			pending = nil
+			last[len(last)-1] = context
+
+			if line.kind == confOpen {
+				last = append(last, "")
+			}
+		case confClose:
+			owner := last[len(last)-1]
+			if owner == "" {
+				owner = line.context
+			}
+
+			addComment(res, owner, "", "", joinComments(strings.Join(pending, "\n"), line.comment))
+
+			pending = nil
+			last = last[:len(last)-1]
+		case confBlank:
+			// like in yaml, leading comments separated by a blank line are the comments of the source
+			if len(last) == 1 && last[0] == "" && len(pending) > 0 {
+				addComment(res, "", strings.Join(pending, "\n"), "", "")
+
+				pending = nil
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	addComment(res, last[0], "", "", strings.Join(pending, "\n"))
+
+	return res, nil
+}
+
+// yamlSourceComments records the comments of a yaml config.
+func yamlSourceComments(src []byte) (map[string]SourceComment, error) {
+	var root yaml.Node
+
+	if err := yaml.Unmarshal(src, &root); err != nil {
+		return nil, err
+	}
+
+	res := map[string]SourceComment{}
+	addComment(res, "", root.HeadComment, "", root.FootComment)
+
+	for _, doc := range root.Content {
+		addComment(res, "", doc.HeadComment, "", "")
+		walkYAMLComments(res, doc, "")
+		addComment(res, "", "", "", doc.FootComment)
+	}
+
+	return res, nil
+}
+
+// walkYAMLComments records the comments of node's children under context.
+func walkYAMLComments(comments map[string]SourceComment, node *yaml.Node, context string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, val := node.Content[i], node.Content[i+1]
+			childCtx := JoinContext(context, key.Value)
+
+			addComment(comments, childCtx, key.HeadComment, strings.TrimSpace(key.LineComment+" "+val.LineComment),
+				joinComments(key.FootComment, val.FootComment))
+			walkYAMLComments(comments, val, childCtx)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			childCtx := JoinContext(context, yamlItemName(item, i))
+
+			addComment(comments, childCtx, item.HeadComment, item.LineComment, item.FootComment)
+			walkYAMLComments(comments, item, childCtx)
+		}
+	case yaml.DocumentNode, yaml.AliasNode, yaml.ScalarNode:
+		return
+	}
+}
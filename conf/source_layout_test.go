@@ -0,0 +1,217 @@
+//go:build unit
+
+package conf
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/go-logr/logr"
+)
+
+const testLayoutConf = `# *** Aerospike Metadata Generated by Asconfig ***
+# aerospike-server-version: 7.0.0
+# *** End Aerospike Metadata ***
+
+# cluster config
+
+service {
+    proto-fd-max    15000 # fds
+}
+
+logging {
+
+    console {
+        context any    info
+    }
+}
+
+namespace zeta {
+    # keep two copies
+    replication-factor    2
+    storage-engine device {
+        file    /opt/a.dat
+        file    /opt/b.dat
+    }
+    # end of zeta
+}
+
+namespace alpha {
+    replication-factor    1
+    storage-engine    memory
+}
+# trailing
+`
+
+const testLayoutYAML = `# cluster config
+
+service:
+  proto-fd-max: 15000 # fds
+namespaces:
+  - name: zeta
+    # keep two copies
+    replication-factor: 2
+  # alpha
+  - name: alpha
+    replication-factor: 1
+`
+
+func TestNewSourceLayoutComments(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		format  asconfig.Format
+		want    map[string]SourceComment
+		wantErr error
+	}{
+		{
+			name:   "conf",
+			src:    testLayoutConf,
+			format: asconfig.AeroConfig,
+			want: map[string]SourceComment{
+				"":                                   {Head: "# cluster config"},
+				"service.proto-fd-max":               {Line: "# fds"},
+				"namespaces.zeta.replication-factor": {Head: "# keep two copies"},
+				"namespaces.zeta.storage-engine":     {Foot: "# end of zeta"},
+				"namespaces.alpha":                   {Foot: "# trailing"},
+			},
+		},
+		{
+			name:   "yaml",
+			src:    testLayoutYAML,
+			format: asconfig.YAML,
+			want: map[string]SourceComment{
+				"":                                   {Head: "# cluster config"},
+				"service.proto-fd-max":               {Line: "# fds"},
+				"namespaces.zeta.replication-factor": {Head: "# keep two copies"},
+				"namespaces.alpha":                   {Head: "# alpha"},
+			},
+		},
+		{
+			name:   "json",
+			src:    `{"service": {"proto-fd-max": 15000}}`,
+			format: JSON,
+			want:   map[string]SourceComment{},
+		},
+		{
+			name:    "toml",
+			src:     "[service]\nproto-fd-max = 15000\n",
+			format:  TOML,
+			wantErr: ErrLayoutFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSourceLayout([]byte(tt.src), tt.format)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewSourceLayout() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got.Comments, tt.want) {
+				t.Errorf("NewSourceLayout() comments = %#v, want %#v", got.Comments, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalWithSourceLayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		format asconfig.Format
+		to     asconfig.Format
+		want   string
+	}{
+		{
+			name:   "conf to yaml",
+			src:    testLayoutConf,
+			format: asconfig.AeroConfig,
+			to:     asconfig.YAML,
+			want: `# cluster config
+
+service:
+    proto-fd-max: 15000 # fds
+logging:
+    - name: console
+      any: info
+namespaces:
+    - name: zeta
+      # keep two copies
+      replication-factor: 2
+      storage-engine:
+        type: device
+        files:
+            - /opt/a.dat
+            - /opt/b.dat
+      # end of zeta
+    - name: alpha
+      replication-factor: 1
+      storage-engine:
+        type: memory
+
+# trailing
+`,
+		},
+		{
+			name:   "yaml to conf",
+			src:    testLayoutYAML,
+			format: asconfig.YAML,
+			to:     asconfig.AeroConfig,
+			want: `# cluster config
+
+service {
+    proto-fd-max    15000 # fds
+}
+
+namespace zeta {
+    # keep two copies
+    replication-factor    2
+}
+
+# alpha
+namespace alpha {
+    replication-factor    1
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := NewASConfigFromBytes(logr.Discard(), []byte(tt.src), tt.format)
+			if err != nil {
+				t.Fatalf("NewASConfigFromBytes() error = %v", err)
+			}
+
+			layout, err := NewSourceLayout([]byte(tt.src), tt.format)
+			if err != nil {
+				t.Fatalf("NewSourceLayout() error = %v", err)
+			}
+
+			got, err := NewConfigMarshaller(cfg, tt.to).WithSourceLayout(layout).MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() error = %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("MarshalText() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalWithSourceLayoutFormat(t *testing.T) {
+	cfg := &mockCFG{confText: "namespace ns1 {}"}
+
+	_, err := NewConfigMarshaller(cfg, JSON).WithSourceLayout(&SourceLayout{}).MarshalText()
+	if !errors.Is(err, ErrLayoutFormat) {
+		t.Errorf("MarshalText() error = %v, want %v", err, ErrLayoutFormat)
+	}
+}
@@ -0,0 +1,254 @@
+package conf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aerospike/aerospike-management-lib/asconfig"
+	"gopkg.in/yaml.v3"
+)
+
+// confIndent is the indentation of a section's content in the Aerospike config format.
+const confIndent = "    "
+
+// marshalWithLayout writes conf in format with the order and comments of layout.
+func marshalWithLayout(conf ConfHandler, format asconfig.Format, layout *SourceLayout) ([]byte, error) {
+	switch format {
+	case asconfig.YAML:
+		return marshalYAMLLayout(conf, layout)
+	case asconfig.AeroConfig:
+		return marshalConfLayout(conf, layout)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrLayoutFormat, format)
+	}
+}
+
+// marshalYAMLLayout writes conf as yaml, ordering keys and list items as they
+// are ordered in the layout and attaching their comments.
+func marshalYAMLLayout(conf ConfHandler, layout *SourceLayout) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(conf.ToMap()); err != nil {
+		return nil, err
+	}
+
+	applyYAMLLayout(layout, &node, "")
+
+	root := layout.Comments[""]
+	doc := &yaml.Node{
+		Kind:        yaml.DocumentNode,
+		Content:     []*yaml.Node{&node},
+		HeadComment: root.Head,
+		FootComment: root.Foot,
+	}
+
+	return yaml.Marshal(doc)
+}
+
+// applyYAMLLayout orders and comments the children of node under context.
+func applyYAMLLayout(layout *SourceLayout, node *yaml.Node, context string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		type pair struct {
+			key, val *yaml.Node
+			order    int
+		}
+
+		pairs := make([]pair, 0, len(node.Content)/2)
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, val := node.Content[i], node.Content[i+1]
+			childCtx := JoinContext(context, key.Value)
+			comment := layout.Comments[childCtx]
+
+			key.HeadComment = comment.Head
+			key.FootComment = comment.Foot
+
+			// line comments of sections follow their key
+			if val.Kind == yaml.ScalarNode {
+				val.LineComment = comment.Line
+			} else {
+				key.LineComment = comment.Line
+			}
+
+			applyYAMLLayout(layout, val, childCtx)
+
+			// keys like name and type are written in the section line of .conf files
+			pairs = append(pairs, pair{key: key, val: val, order: layout.order(childCtx, context)})
+		}
+
+		sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].order < pairs[j].order })
+
+		node.Content = node.Content[:0]
+		for _, p := range pairs {
+			node.Content = append(node.Content, p.key, p.val)
+		}
+	case yaml.SequenceNode:
+		orders := make(map[*yaml.Node]int, len(node.Content))
+
+		for i, item := range node.Content {
+			childCtx := JoinContext(context, yamlItemName(item, i))
+			comment := layout.Comments[childCtx]
+
+			item.HeadComment = comment.Head
+			item.LineComment = comment.Line
+			item.FootComment = comment.Foot
+
+			applyYAMLLayout(layout, item, childCtx)
+
+			orders[item] = layout.order(childCtx)
+		}
+
+		sort.SliceStable(node.Content, func(i, j int) bool {
+			return orders[node.Content[i]] < orders[node.Content[j]]
+		})
+	case yaml.DocumentNode, yaml.AliasNode, yaml.ScalarNode:
+		return
+	}
+}
+
+// confNode is a key or section of an Aerospike config file.
+type confNode struct {
+	text     string
+	contexts []string
+	section  bool
+	children []*confNode
+}
+
+// marshalConfLayout writes conf in the Aerospike config format, ordering keys
+// and sections as they are ordered in the layout and attaching their comments.
+func marshalConfLayout(conf ConfHandler, layout *SourceLayout) ([]byte, error) {
+	root := &confNode{section: true}
+	stack := []*confNode{root}
+
+	err := scanConf([]byte(conf.ToConfFile()), func(line confLine) {
+		parent := stack[len(stack)-1]
+
+		switch line.kind {
+		case confKey, confOpen:
+			// comments of keys are recorded with their yaml names
+			node := &confNode{text: line.text, contexts: []string{line.context}, section: line.kind == confOpen}
+			if line.plural != "" {
+				node.contexts = []string{line.plural, line.context}
+			}
+
+			parent.children = append(parent.children, node)
+
+			if node.section {
+				stack = append(stack, node)
+			}
+		case confClose:
+			stack = stack[:len(stack)-1]
+		case confBlank, confComment:
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w := &confLayoutWriter{layout: layout, written: map[string]bool{}}
+
+	rootComment := layout.Comments[""]
+	w.writeComment(rootComment.Head, "")
+
+	if rootComment.Head != "" {
+		w.sb.WriteString("\n")
+	}
+
+	w.writeChildren(root, "")
+	w.writeComment(rootComment.Foot, "")
+
+	return []byte(w.sb.String()), nil
+}
+
+// confLayoutWriter writes a tree of confNodes. Comments are only written once
+// for keys, like file, that are written on several lines.
+type confLayoutWriter struct {
+	sb      strings.Builder
+	layout  *SourceLayout
+	written map[string]bool
+}
+
+func (w *confLayoutWriter) writeChildren(parent *confNode, indent string) {
+	sort.SliceStable(parent.children, func(i, j int) bool {
+		return w.layout.order(parent.children[i].contexts...) < w.layout.order(parent.children[j].contexts...)
+	})
+
+	for i, node := range parent.children {
+		// sections are separated by a blank line
+		if node.section && (i > 0 || indent != "") {
+			w.sb.WriteString("\n")
+		}
+
+		comment := w.comment(node)
+
+		w.writeComment(comment.Head, indent)
+		w.sb.WriteString(indent + node.text)
+
+		if comment.Line != "" {
+			w.sb.WriteString(" " + comment.Line)
+		}
+
+		w.sb.WriteString("\n")
+
+		if node.section {
+			w.writeChildren(node, indent+confIndent)
+			w.sb.WriteString(indent + sectionClose + "\n")
+		}
+
+		w.writeComment(comment.Foot, indent)
+	}
+}
+
+// comment returns the comments of node that were not already written, with
+// the comments of the keys, like name and type, written in its section line.
+func (w *confLayoutWriter) comment(node *confNode) SourceComment {
+	var res SourceComment
+
+	for _, context := range node.contexts {
+		comment, ok := w.layout.Comments[context]
+		if !ok {
+			continue
+		}
+
+		if !w.written[context] {
+			w.written[context] = true
+			res = comment
+		}
+
+		break
+	}
+
+	context := node.contexts[len(node.contexts)-1]
+
+	for _, key := range []string{"name", "type"} {
+		keyCtx := JoinContext(context, key)
+
+		comment, ok := w.layout.Comments[keyCtx]
+		if !ok || w.written[keyCtx] {
+			continue
+		}
+
+		w.written[keyCtx] = true
+		res.Head = joinComments(res.Head, comment.Head)
+		res.Line = strings.TrimSpace(res.Line + " " + comment.Line)
+		res.Foot = joinComments(res.Foot, comment.Foot)
+	}
+
+	return res
+}
+
+func (w *confLayoutWriter) writeComment(comment, indent string) {
+	if comment == "" {
+		return
+	}
+
+	for _, line := range strings.Split(comment, "\n") {
+		if line == "" {
+			w.sb.WriteString("\n")
+			continue
+		}
+
+		w.sb.WriteString(indent + line + "\n")
+	}
+}
@@ -0,0 +1,313 @@
+package conf
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aerospike/aerospike-management-lib/asconfig"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrUnbalancedSection = errors.New("unbalanced section braces in config")
+
+const (
+	sectionOpen     = "{"
+	sectionClose    = "}"
+	loggingContext  = "logging"
+	logContextKey   = "context"
+	confCommentChar = "#"
+	keyName         = "name"
+)
+
+// typedSections are .conf sections whose value is a type rather than a name.
+// Ex: storage-engine device {.
+var typedSections = map[string]struct{}{
+	"storage-engine": {},
+	"index-type":     {},
+	"sindex-type":    {},
+}
+
+// SourcePosition is the 1 based line and column where a
+// configuration key or section appears in its source.
+type SourcePosition struct {
+	Line   int
+	Column int
+}
+
+// SourcePositions maps dotted, name resolved configuration contexts
+// like "namespaces.test.storage-engine" to their position in the source.
+// The contexts use the same format produced by jsonToConfigContext.
+type SourcePositions map[string]SourcePosition
+
+// NewSourcePositions indexes the position of every key and section in src.
+func NewSourcePositions(src []byte, format asconfig.Format) (SourcePositions, error) {
+	switch format {
+	case asconfig.YAML, JSON:
+		return yamlSourcePositions(src)
+	case asconfig.AeroConfig:
+		return confSourcePositions(src)
+	case asconfig.Invalid:
+		return nil, fmt.Errorf("%w %s", asconfig.ErrInvalidFormat, format)
+	default:
+		return nil, fmt.Errorf("%w %s", asconfig.ErrInvalidFormat, format)
+	}
+}
+
+// Lookup returns the position of context. If context is not in the source, for
+// example a required field that is missing, the position of its closest
+// enclosing section is returned instead.
+func (sp SourcePositions) Lookup(context string) (SourcePosition, bool) {
+	for context != "" {
+		if pos, ok := sp[context]; ok {
+			return pos, true
+		}
+
+		idx := strings.LastIndex(context, ".")
+		if idx < 0 {
+			break
+		}
+
+		context = context[:idx]
+	}
+
+	return SourcePosition{}, false
+}
+
+// add records the position of context, only the first occurrence is kept.
+func (sp SourcePositions) add(context string, line, column int) {
+	if _, ok := sp[context]; !ok {
+		sp[context] = SourcePosition{Line: line, Column: column}
+	}
+}
+
+// yamlSourcePositions indexes the key positions of a yaml config.
+func yamlSourcePositions(src []byte) (SourcePositions, error) {
+	var root yaml.Node
+
+	if err := yaml.Unmarshal(src, &root); err != nil {
+		return nil, err
+	}
+
+	res := SourcePositions{}
+
+	for _, doc := range root.Content {
+		walkYAMLNode(res, doc, "")
+	}
+
+	return res, nil
+}
+
+// walkYAMLNode records the positions of node's children under context.
+// List items are identified by their "name" field when they have one,
+// matching the way validation error contexts are resolved.
+func walkYAMLNode(sp SourcePositions, node *yaml.Node, context string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, val := node.Content[i], node.Content[i+1]
+			childCtx := JoinContext(context, key.Value)
+
+			sp.add(childCtx, key.Line, key.Column)
+			walkYAMLNode(sp, val, childCtx)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			childCtx := JoinContext(context, yamlItemName(item, i))
+
+			sp.add(childCtx, item.Line, item.Column)
+			walkYAMLNode(sp, item, childCtx)
+		}
+	case yaml.DocumentNode, yaml.AliasNode, yaml.ScalarNode:
+		return
+	}
+}
+
+// yamlItemName returns the value of a list item's name field or its index.
+func yamlItemName(item *yaml.Node, index int) string {
+	if item.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			if item.Content[i].Value == keyName && item.Content[i+1].Kind == yaml.ScalarNode {
+				return item.Content[i+1].Value
+			}
+		}
+	}
+
+	return strconv.Itoa(index)
+}
+
+// confSourcePositions indexes the key and section positions of an Aerospike .conf file.
+// Section and key names are converted to the plural forms used by the yaml format.
+// Ex: "namespace test {" is recorded as "namespaces.test".
+func confSourcePositions(src []byte) (SourcePositions, error) {
+	res := SourcePositions{}
+
+	err := scanConf(src, func(line confLine) {
+		switch line.kind {
+		case confOpen:
+			// named sections also record the list they belong to
+			if line.list != "" {
+				res.add(line.list, line.num, line.column)
+			}
+
+			res.add(line.context, line.num, line.column)
+		case confKey:
+			res.add(line.context, line.num, line.column)
+
+			if line.plural != "" {
+				res.add(line.plural, line.num, line.column)
+			}
+		case confBlank, confComment, confClose:
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// confLineKind is the kind of a line of an Aerospike .conf file.
+type confLineKind int
+
+const (
+	confBlank confLineKind = iota
+	confComment
+	confKey
+	confOpen
+	confClose
+)
+
+// confLine is a line of an Aerospike .conf file and the context of its key or section.
+type confLine struct {
+	kind   confLineKind
+	num    int
+	column int
+	// text is the line without indentation and comment.
+	text string
+	// comment is the comment of the line, including the comment char.
+	comment string
+	// context is the context of the key, the section opened or the section closed.
+	context string
+	// list is the context of the list of a named section, like "namespaces".
+	list string
+	// plural is the context of a key in the plural form used by the yaml format.
+	plural string
+}
+
+// scanConf calls visit with every line of an Aerospike .conf file.
+func scanConf(src []byte, visit func(confLine)) error {
+	stack := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+
+		raw := scanner.Text()
+		code, comment, hasComment := strings.Cut(raw, confCommentChar)
+		res := confLine{
+			num:    lineNum,
+			column: strings.IndexFunc(raw, func(r rune) bool { return !unicode.IsSpace(r) }) + 1,
+			text:   strings.TrimSpace(code),
+		}
+
+		if hasComment {
+			res.comment = strings.TrimRightFunc(confCommentChar+comment, unicode.IsSpace)
+		}
+
+		tok := strings.Fields(code)
+		if len(tok) == 0 {
+			res.kind = confBlank
+			if hasComment {
+				res.kind = confComment
+			}
+
+			visit(res)
+
+			continue
+		}
+
+		parent := ""
+
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+
+		switch {
+		case tok[0] == sectionClose:
+			if len(stack) == 0 {
+				return fmt.Errorf("%w: line %d", ErrUnbalancedSection, lineNum)
+			}
+
+			res.kind = confClose
+			res.context = parent
+			stack = stack[:len(stack)-1]
+		case tok[len(tok)-1] == sectionOpen:
+			res.kind = confOpen
+			res.list, res.context = confSectionContext(parent, tok[:len(tok)-1])
+			stack = append(stack, res.context)
+		case tok[0] == logContextKey && len(tok) > 2:
+			// logging contexts are written as "context <name> <level>"
+			res.kind = confKey
+			res.context = JoinContext(parent, tok[1])
+		default:
+			res.kind = confKey
+			res.context = JoinContext(parent, tok[0])
+
+			if plural := asconfig.PluralOf(tok[0]); plural != tok[0] {
+				res.plural = JoinContext(parent, plural)
+			}
+		}
+
+		visit(res)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if len(stack) > 0 {
+		return fmt.Errorf("%w: %s is not closed", ErrUnbalancedSection, stack[len(stack)-1])
+	}
+
+	return nil
+}
+
+// confSectionContext returns the context of a section opened by tok within parent.
+// For named list sections, like "namespace test", the context of the list is also returned.
+func confSectionContext(parent string, tok []string) (string, string) {
+	name := tok[0]
+
+	// logging sinks are identified by their name, or their
+	// file path, within the logging list
+	if parent == loggingContext {
+		return "", JoinContext(parent, tok[len(tok)-1])
+	}
+
+	if len(tok) == 1 {
+		return "", JoinContext(parent, name)
+	}
+
+	if _, ok := typedSections[name]; ok {
+		return "", JoinContext(parent, name)
+	}
+
+	list := JoinContext(parent, asconfig.PluralOf(name))
+
+	return list, JoinContext(list, tok[1])
+}
+
+// JoinContext appends key to a dotted configuration context.
+// Ex: namespaces.test and replication-factor give namespaces.test.replication-factor.
+func JoinContext(context, key string) string {
+	if context == "" {
+		return key
+	}
+
+	return context + "." + key
+}
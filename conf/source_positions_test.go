@@ -0,0 +1,149 @@
+//go:build unit
+
+package conf
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aerospike/aerospike-management-lib/asconfig"
+)
+
+const testPositionsYAML = `service:
+  proto-fd-max: 15000
+logging:
+  - name: console
+    any: info
+namespaces:
+  - name: test
+    replication-factor: 2
+    storage-engine:
+      type: memory
+`
+
+const testPositionsConf = `# comment
+service {
+	proto-fd-max 15000
+}
+
+logging {
+	console {
+		context any info
+	}
+	file /var/log/aerospike.log {
+		context misc warning
+	}
+}
+
+network {
+	service {
+		address any
+	}
+}
+
+namespace test {
+	replication-factor 2 # comment
+	storage-engine device {
+		file /opt/aerospike/test.dat
+	}
+}
+`
+
+func TestNewSourcePositions(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		format  asconfig.Format
+		want    map[string]SourcePosition
+		wantErr bool
+	}{
+		{
+			name:   "yaml",
+			src:    testPositionsYAML,
+			format: asconfig.YAML,
+			want: map[string]SourcePosition{
+				"service.proto-fd-max":               {Line: 2, Column: 3},
+				"logging.console":                    {Line: 4, Column: 5},
+				"logging.console.any":                {Line: 5, Column: 5},
+				"namespaces.test":                    {Line: 7, Column: 5},
+				"namespaces.test.replication-factor": {Line: 8, Column: 5},
+				"namespaces.test.storage-engine":     {Line: 9, Column: 5},
+			},
+		},
+		{
+			name:   "conf",
+			src:    testPositionsConf,
+			format: asconfig.AeroConfig,
+			want: map[string]SourcePosition{
+				"service":                              {Line: 2, Column: 1},
+				"service.proto-fd-max":                 {Line: 3, Column: 2},
+				"logging.console":                      {Line: 7, Column: 2},
+				"logging.console.any":                  {Line: 8, Column: 3},
+				"logging./var/log/aerospike.log.misc":  {Line: 11, Column: 3},
+				"network.service.address":              {Line: 17, Column: 3},
+				"network.service.addresses":            {Line: 17, Column: 3},
+				"namespaces":                           {Line: 21, Column: 1},
+				"namespaces.test":                      {Line: 21, Column: 1},
+				"namespaces.test.replication-factor":   {Line: 22, Column: 2},
+				"namespaces.test.storage-engine":       {Line: 23, Column: 2},
+				"namespaces.test.storage-engine.files": {Line: 24, Column: 3},
+			},
+		},
+		{
+			name:    "conf unclosed section",
+			src:     "service {\n",
+			format:  asconfig.AeroConfig,
+			wantErr: true,
+		},
+		{
+			name:    "conf extra closing brace",
+			src:     "}\n",
+			format:  asconfig.AeroConfig,
+			wantErr: true,
+		},
+		{
+			name:    "invalid format",
+			src:     testPositionsYAML,
+			format:  asconfig.Invalid,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSourcePositions([]byte(tt.src), tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSourcePositions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			for ctx, want := range tt.want {
+				if pos := got[ctx]; !reflect.DeepEqual(pos, want) {
+					t.Errorf("NewSourcePositions()[%q] = %v, want %v", ctx, pos, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSourcePositions_Lookup(t *testing.T) {
+	sp := SourcePositions{
+		"namespaces.test":                    {Line: 1, Column: 1},
+		"namespaces.test.replication-factor": {Line: 2, Column: 5},
+	}
+
+	tests := []struct {
+		context string
+		want    SourcePosition
+		found   bool
+	}{
+		{context: "namespaces.test.replication-factor", want: SourcePosition{Line: 2, Column: 5}, found: true},
+		{context: "namespaces.test.storage-engine.devices", want: SourcePosition{Line: 1, Column: 1}, found: true},
+		{context: "service", found: false},
+		{context: "", found: false},
+	}
+	for _, tt := range tests {
+		got, found := sp.Lookup(tt.context)
+		if found != tt.found || got != tt.want {
+			t.Errorf("Lookup(%q) = %v, %v, want %v, %v", tt.context, got, found, tt.want, tt.found)
+		}
+	}
+}
@@ -997,23 +997,23 @@ var validateTests = []validateTest{
 		arguments:   []string{"validate", "-a", "7.0.0", "-l", "panic", filepath.Join(extraTestPath, "server64", "server64.yaml")},
 		expectError: true,
 		source:      filepath.Join(extraTestPath, "server64", "server64.yaml"),
-		expectedResult: `context: namespaces.ns1
+		expectedResult: `context: namespaces.ns1 (line 14, column 7)
 	- description: Additional property memory-size is not allowed, error-type: additional_property_not_allowed
-context: namespaces.ns1.index-type
+context: namespaces.ns1.index-type (line 14, column 7)
 	- description: Additional property mounts-high-water-pct is not allowed, error-type: additional_property_not_allowed
 	- description: Additional property mounts-size-limit is not allowed, error-type: additional_property_not_allowed
 	- description: mounts-budget is required, error-type: required
-context: namespaces.ns1.sindex-type
+context: namespaces.ns1.sindex-type (line 23, column 7)
 	- description: Additional property mounts-high-water-pct is not allowed, error-type: additional_property_not_allowed
 	- description: Additional property mounts-size-limit is not allowed, error-type: additional_property_not_allowed
 	- description: mounts-budget is required, error-type: required
-context: namespaces.ns1.storage-engine
+context: namespaces.ns1.storage-engine (line 29, column 7)
 	- description: devices is required, error-type: required
-context: namespaces.ns2
+context: namespaces.ns2 (line 5, column 7)
 	- description: Additional property memory-size is not allowed, error-type: additional_property_not_allowed
-context: namespaces.ns2.storage-engine
+context: namespaces.ns2.storage-engine (line 12, column 7)
 	- description: devices is required, error-type: required
-context: service
+context: service (line 47, column 1)
 	- description: cluster-name is required, error-type: required
 `,
 	},